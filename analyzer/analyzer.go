@@ -0,0 +1,136 @@
+// Package analyzer exposes funcorder-fix as a go/analysis Analyzer, so that the
+// checks and fixes can run inside go vet, gopls and golangci-lint.
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/detector"
+	"github.com/vajrock/funcorder-fix/internal/fixer"
+)
+
+const doc = `check and fix the order of struct methods
+
+The funcorderfix analyzer reports methods that violate the funcorder rules:
+constructors (by default New*, Must*, Or*) must come before other methods of
+the struct, and exported methods must come before unexported ones. With
+-alphabetical, the methods of each group must also be sorted by name.
+Structs, methods and files marked with //nolint:funcorder or
+//funcorder:ignore are skipped, unless -no-ignore is set. Every
+diagnostic carries a suggested fix that reorders the methods of the struct in
+place, preserving doc comments and any code between the methods.`
+
+// Analyzer reports funcorder violations with suggested fixes.
+var Analyzer = New(config.DefaultConfig())
+
+// New creates an Analyzer that uses cfg for its rule settings.
+// The rules and the way constructors are identified are also exposed as
+// analyzer flags.
+func New(cfg *config.Config) *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name: "funcorderfix",
+		Doc:  doc,
+		URL:  "https://github.com/vajrock/funcorder-fix",
+		Run: func(pass *analysis.Pass) (any, error) {
+			return run(pass, cfg)
+		},
+	}
+	a.Flags.BoolVar(&cfg.CheckConstructor, "constructor", cfg.CheckConstructor, "check constructor ordering")
+	a.Flags.BoolVar(&cfg.CheckExported, "exported", cfg.CheckExported, "check exported before unexported ordering")
+	a.Flags.BoolVar(&cfg.Alphabetical, "alphabetical", cfg.Alphabetical, "check that methods are sorted by name within their group")
+	a.Flags.BoolVar(&cfg.NoIgnore, "no-ignore", cfg.NoIgnore, "disable //nolint:funcorder and //funcorder:ignore directives")
+	a.Flags.BoolVar(&cfg.TypeCheck, "types", cfg.TypeCheck, "identify constructors by return type instead of name prefix")
+	a.Flags.Var(config.NewListValue(&cfg.ConstructorPrefixes, ","), "constructor-prefix", "comma-separated constructor name prefixes, replacing the defaults")
+	a.Flags.Var(config.NewListValue(&cfg.ConstructorPatterns, ""), "constructor-pattern", "regular expression matching constructor names (repeatable)")
+	return a
+}
+
+// run analyzes every file of the package independently.
+func run(pass *analysis.Pass, cfg *config.Config) (any, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var typed detector.TypedConstructors
+	if cfg.TypeCheck {
+		typed = detector.ResolveConstructors(pass.Files, pass.TypesInfo, nil)
+	}
+
+	for _, file := range pass.Files {
+		if err := runFile(pass, cfg, typed, file); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// runFile reports the violations of a single file together with the fix that
+// reorders the methods of the offending structs. typed, if not nil, holds the
+// constructors resolved from type information.
+func runFile(pass *analysis.Pass, cfg *config.Config, typed detector.TypedConstructors, file *ast.File) error {
+	tf := pass.Fset.File(file.Pos())
+	if tf == nil {
+		return nil
+	}
+
+	det := detector.NewDetector(pass.Fset, cfg)
+	if typed != nil {
+		det.SetTypedConstructors(typed)
+	}
+	report := det.Detect(file, tf.Name())
+	if !report.HasViolations() {
+		return nil
+	}
+
+	src, err := pass.ReadFile(tf.Name())
+	if err != nil {
+		return fmt.Errorf("read %s: %w", tf.Name(), err)
+	}
+	// Files preprocessed by cgo no longer match their source on disk.
+	if len(src) != tf.Size() {
+		return nil
+	}
+
+	structs := det.CollectStructMethods(file)
+	needsReorder := make(map[string]*detector.StructMethods)
+	for name, sm := range structs {
+		if sm.NeedsReordering() {
+			needsReorder[name] = sm
+		}
+	}
+
+	reorderer := fixer.NewReorderer(pass.Fset)
+	replacements, err := reorderer.BuildReplacements(file, src, needsReorder)
+	if err != nil {
+		return fmt.Errorf("build fix for %s: %w", tf.Name(), err)
+	}
+
+	edits := make(map[string][]analysis.TextEdit)
+	for _, rep := range replacements {
+		edits[rep.StructName] = append(edits[rep.StructName], analysis.TextEdit{
+			Pos:     tf.Pos(rep.Start),
+			End:     tf.Pos(rep.End),
+			NewText: []byte(rep.Text),
+		})
+	}
+
+	for _, v := range report.Violations {
+		diag := analysis.Diagnostic{
+			Pos:      v.MethodPos,
+			Category: v.Type.String(),
+			Message:  v.Message,
+		}
+		if structEdits := edits[v.StructName]; len(structEdits) > 0 {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Reorder methods of %s", v.StructName),
+				TextEdits: structEdits,
+			}}
+		}
+		pass.Report(diag)
+	}
+	return nil
+}
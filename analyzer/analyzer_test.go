@@ -0,0 +1,45 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/vajrock/funcorder-fix/analyzer"
+	"github.com/vajrock/funcorder-fix/internal/config"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer.Analyzer, "a")
+}
+
+func TestAnalyzer_RulesDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CheckConstructor = false
+	cfg.CheckExported = false
+
+	// The package has no // want comments, so any diagnostic fails the test.
+	analysistest.Run(t, analysistest.TestData(), analyzer.New(cfg), "disabled")
+}
+
+func TestAnalyzer_Types(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.TypeCheck = true
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer.New(cfg), "typed")
+}
+
+func TestAnalyzer_ConstructorNaming(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ConstructorPrefixes = []string{"New", "Open"}
+	cfg.ConstructorPatterns = []string{`^From[A-Z]`}
+
+	analysistest.Run(t, analysistest.TestData(), analyzer.New(cfg), "naming")
+}
+
+func TestAnalyzer_Alphabetical(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Alphabetical = true
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer.New(cfg), "alphabetical")
+}
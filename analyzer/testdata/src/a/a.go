@@ -0,0 +1,30 @@
+package a
+
+type Server struct {
+	port int
+}
+
+// shutdown stops the server.
+func (s *Server) shutdown() { // want "unexported method shutdown should appear after exported method Listen"
+	s.port = 0
+}
+
+func defaultPort() int {
+	return 8080
+}
+
+// Listen starts accepting connections.
+func (s *Server) Listen() {
+	_ = s.port
+}
+
+func (s *Server) Clone() *Server { return &Server{port: s.port} }
+
+func (s *Server) NewCopy() *Server { // want "constructor NewCopy should appear before exported method Listen"
+	return &Server{port: s.port}
+}
+
+type Ordered struct{}
+
+func (o Ordered) Run()  {}
+func (o Ordered) stop() {}
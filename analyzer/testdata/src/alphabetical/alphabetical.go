@@ -0,0 +1,18 @@
+package alphabetical
+
+type Queue struct{}
+
+// Push adds an item.
+func (q *Queue) Push() {}
+
+// Pop removes an item.
+func (q *Queue) Pop() { // want "exported method Pop should appear before exported method Push in alphabetical order"
+}
+
+// Len returns the number of items.
+func (q *Queue) Len() int { // want "exported method Len should appear before exported method Push in alphabetical order"
+	return 0
+}
+
+// grow enlarges the buffer.
+func (q *Queue) grow() {}
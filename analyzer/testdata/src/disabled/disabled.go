@@ -0,0 +1,8 @@
+package disabled
+
+// Worker violates both rules, but the test runs with every rule disabled.
+type Worker struct{}
+
+func (w *Worker) stop()              {}
+func (w *Worker) Start()             {}
+func (w *Worker) NewWorker() *Worker { return &Worker{} }
@@ -0,0 +1,12 @@
+package naming
+
+type Store struct{}
+
+func (s *Store) Run() {}
+
+// OrDefault is not a constructor with the configured prefixes.
+func (s *Store) OrDefault() *Store { return s }
+
+func (s *Store) OpenSession() *Store { return s } // want "constructor OpenSession should appear before exported method Run"
+
+func (s *Store) FromJSON() *Store { return s } // want "constructor FromJSON should appear before exported method Run"
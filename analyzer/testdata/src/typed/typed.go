@@ -0,0 +1,11 @@
+package typed
+
+type Order struct{ id int }
+
+// Origin is not a constructor despite its prefix.
+func (o *Order) Origin() string { return "" }
+
+// Build returns a copy of the order.
+func (o *Order) Build() *Order { // want "constructor Build should appear before exported method Origin"
+	return &Order{id: o.id}
+}
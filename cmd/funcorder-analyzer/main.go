@@ -0,0 +1,18 @@
+// Command funcorder-analyzer runs the funcorderfix analyzer as a standalone
+// tool built on the go/analysis single-checker driver.
+//
+// Usage:
+//
+//	funcorder-analyzer ./...
+//	funcorder-analyzer -fix ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/vajrock/funcorder-fix/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
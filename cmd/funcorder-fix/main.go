@@ -2,16 +2,44 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/vajrock/funcorder-fix/internal/cache"
 	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/detector"
 	"github.com/vajrock/funcorder-fix/internal/fixer"
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+// Exit codes.
+const (
+	// exitClean means no violations were found and no file would change.
+	exitClean = 0
+
+	// exitViolations means violations were found in check mode, or files
+	// would change (or were changed) with --check.
+	exitViolations = 1
+
+	// exitError means a file could not be processed or written.
+	exitError = 2
 )
 
+// Version is the version of the tool, set at build time with
+// -ldflags "-X main.Version=...".
+var Version = "dev"
+
 var (
 	flagFix          bool
 	flagWrite        bool
@@ -22,6 +50,30 @@ var (
 	flagNoConstructor bool
 	flagExported     bool
 	flagNoExported   bool
+	flagAlphabetical bool
+	flagPackage      bool
+	flagMoveMethods  bool
+	flagStandalone   bool
+	flagBeforeMethods bool
+	flagTypes        bool
+	flagVerifyTypes  bool
+	flagCheck        bool
+	flagOrder        bool
+	flagNoIgnore     bool
+	flagGenerated    bool
+	flagGitIgnore    bool
+	flagNoCache      bool
+	flagStdin        bool
+	flagFormat       string
+	flagCacheDir     string
+	flagStdinName    string
+	flagJobs         int
+
+	flagConstructorPrefixes = config.DefaultConfig().ConstructorPrefixes
+	flagConstructorPatterns []string
+	flagExclude             []string
+	flagTags                []string
+	flagInclude             []string
 )
 
 func init() {
@@ -34,11 +86,38 @@ func init() {
 	flag.BoolVar(&flagNoConstructor, "no-constructor", false, "disable constructor ordering check")
 	flag.BoolVar(&flagExported, "exported", true, "check exported before unexported ordering")
 	flag.BoolVar(&flagNoExported, "no-exported", false, "disable exported ordering check")
+	flag.BoolVar(&flagAlphabetical, "alphabetical", false, "check that methods are sorted by name within their group")
+	flag.BoolVar(&flagPackage, "package", false, "group methods across all files of a package")
+	flag.BoolVar(&flagMoveMethods, "move-methods", false, "move methods into the file declaring their struct (implies -package)")
+	flag.BoolVar(&flagStandalone, "standalone-constructors", false, "check that constructor functions without a receiver follow their struct")
+	flag.BoolVar(&flagBeforeMethods, "constructors-before-methods", false, "only require constructor functions to follow their struct and precede its methods, as upstream funcorder does")
+	flag.BoolVar(&flagTypes, "types", false, "identify constructors by return type using type information")
+	flag.BoolVar(&flagVerifyTypes, "verify-types", false, "type-check each fixed package before and after the fix and reject fixes that change its types")
+	flag.BoolVar(&flagCheck, "check", false, "exit with status 1 if any file has violations or would be changed, also with --fix")
+	flag.BoolVar(&flagCheck, "exit-code", false, "alias for -check")
+	flag.IntVar(&flagJobs, "j", 0, "number of files or packages processed concurrently (default GOMAXPROCS)")
+	flag.StringVar(&flagFormat, "format", "text", "output format: text or "+strings.Join(report.Formats, ", "))
+	flag.BoolVar(&flagNoIgnore, "no-ignore", false, "disable //nolint:funcorder and //funcorder:ignore directives to audit suppressed code")
+	flag.BoolVar(&flagGenerated, "include-generated", false, "process generated files (// Code generated ... DO NOT EDIT.) when walking directories")
+	flag.Var(config.NewListValue(&flagExclude, ""), "exclude", "glob pattern of files and directories to skip when walking directories (repeatable)")
+	flag.Var(config.NewListValue(&flagInclude, ""), "include", "glob pattern of files to process when walking directories, skipping all others (repeatable)")
+	flag.Var(config.NewListValue(&flagTags, ","), "tags", "comma-separated build tags used to resolve package patterns and type-check, as with go build -tags")
+	flag.BoolVar(&flagGitIgnore, "gitignore", false, "skip files and directories ignored by .gitignore files")
+	flag.StringVar(&flagCacheDir, "cache-dir", "", "directory of the result cache (default funcorder-fix in the user cache directory)")
+	flag.BoolVar(&flagNoCache, "no-cache", false, "process every file without reading or writing the result cache")
+	flag.BoolVar(&flagStdin, "stdin", false, "fix the Go source read from stdin and write it to stdout, unchanged if there is nothing to fix")
+	flag.StringVar(&flagStdinName, "stdin-filename", "", "file name of the source read from stdin, used in messages and to find its configuration (implies -stdin)")
+	flag.BoolVar(&flagOrder, "expected-order", false, "print the expected method order of every struct with violations")
+	flag.Var(config.NewListValue(&flagConstructorPrefixes, ","), "constructor-prefix", "comma-separated constructor name prefixes, replacing the defaults (repeatable)")
+	flag.Var(config.NewListValue(&flagConstructorPatterns, ""), "constructor-pattern", "regular expression matching constructor names (repeatable)")
 }
 
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [path ...]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [flags] config [path]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [flags] cache clean\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [flags] --stdin [--stdin-filename path] < file.go\n", os.Args[0])
 		fmt.Fprintln(os.Stderr, "\nFuncorder-fix automatically fixes funcorder linter violations.")
 		fmt.Fprintln(os.Stderr, "\nFlags:")
 		flag.PrintDefaults()
@@ -51,19 +130,49 @@ func main() {
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "  # Show diff of changes")
 		fmt.Fprintln(os.Stderr, "  funcorder-fix --fix -d ./...")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "  # Print the effective configuration of a directory")
+		fmt.Fprintln(os.Stderr, "  funcorder-fix config ./internal")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "  # Fix an editor buffer, for format-on-save")
+		fmt.Fprintln(os.Stderr, "  funcorder-fix --stdin-filename internal/server.go < internal/server.go")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "  # Remove the cached results of previous runs")
+		fmt.Fprintln(os.Stderr, "  funcorder-fix cache clean")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintf(os.Stderr, "Settings are read from %s files in the target directory and its parents;\n", config.FileName)
+		fmt.Fprintln(os.Stderr, "nested files override their parents and flags override the files.")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Exit status is 0 if no violations were found, 1 if violations were found")
+		fmt.Fprintln(os.Stderr, "(or, with --check, files would change) and 2 on errors.")
 	}
 
 	flag.Parse()
 
-	// Build configuration
-	cfg := config.DefaultConfig()
-	cfg.Fix = flagFix
-	cfg.Write = flagWrite
-	cfg.Diff = flagDiff
-	cfg.List = flagList
-	cfg.Verbose = flagVerbose
-	cfg.CheckConstructor = flagConstructor && !flagNoConstructor
-	cfg.CheckExported = flagExported && !flagNoExported
+	// The config and cache subcommands may be followed by more flags; use
+	// ./config or ./cache to process a directory of that name.
+	subcommand := ""
+	if flag.Arg(0) == "config" || flag.Arg(0) == "cache" {
+		subcommand = flag.Arg(0)
+		_ = flag.CommandLine.Parse(flag.Args()[1:])
+	}
+
+	if subcommand == "cache" {
+		os.Exit(runCache(flag.Args()))
+	}
+
+	// Build configuration: output and audit settings come from the flags, rule
+	// settings from the configuration files, overridden by explicit flags.
+	stdin := flagStdin || flagStdinName != ""
+	base := config.DefaultConfig()
+	// Reading stdin is a filter: it always fixes.
+	base.Fix = flagFix || stdin
+	base.Write = flagWrite
+	base.Diff = flagDiff
+	base.List = flagList
+	base.Verbose = flagVerbose
+	base.NoIgnore = flagNoIgnore
+	loader := config.NewLoader(base, flagOverrides())
 
 	// Get paths to process
 	paths := flag.Args()
@@ -71,83 +180,483 @@ func main() {
 		paths = []string{"."}
 	}
 
+	if subcommand == "config" {
+		os.Exit(printConfig(loader, paths[0]))
+	}
+	if stdin {
+		os.Exit(runStdin(loader))
+	}
+
+	// Settings that apply to the whole run are taken from the configuration
+	// of the first path.
+	resolved, err := loader.Load(configDir(paths[0]))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitError)
+	}
+	cfg := resolved.Config
+
+	// Machine-readable formats replace the text output on stdout and stderr.
+	var reporter report.Reporter
+	if cfg.Format != "text" {
+		r, err := report.New(cfg.Format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		reporter = r
+	}
+
 	// Create fixer
 	f := fixer.NewFixer(cfg)
+	f.SetConfigLoader(loader)
+	useCache(f)
 
 	// Process all paths
 	totalViolations := 0
 	totalFixed := 0
 	hasErrors := false
 
-	for _, path := range paths {
-		results := processPath(f, path, cfg)
+	// Results are handled as they are produced, so that the contents of
+	// every file need not be held in memory at once.
+	handle := func(result *fixer.Result) {
+		if reporter != nil {
+			reporter.Add(result.ReportFile())
+		}
 
-		for _, result := range results {
-			if result.Error != nil {
-				fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", result.FilePath, result.Error)
-				hasErrors = true
-				continue
-			}
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", result.FilePath, result.Error)
+			hasErrors = true
+			return
+		}
 
-			if result.Violations > 0 {
-				totalViolations += result.Violations
+		if result.Violations > 0 {
+			totalViolations += result.Violations
 
-				if cfg.List {
-					fmt.Println(result.FilePath)
-				} else if cfg.Verbose || !cfg.Fix {
-					fmt.Fprintf(os.Stderr, "%s: %d violations\n", result.FilePath, result.Violations)
-				}
+			if reporter == nil {
+				printViolations(result, cfg)
+			}
+		}
 
-				if result.Fixed {
-					totalFixed++
-					if err := f.WriteResult(result); err != nil {
-						fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", result.FilePath, err)
-						hasErrors = true
-					} else if cfg.Write {
-						if cfg.Verbose {
-							fmt.Fprintf(os.Stderr, "Fixed: %s\n", result.FilePath)
-						}
-					}
+		// In package mode a file may change without violations of its
+		// own, e.g. when it receives methods moved from another file.
+		if result.Fixed {
+			totalFixed++
+			if reporter != nil && !cfg.Write {
+				// Fixed content would corrupt the report on stdout.
+				return
+			}
+			if err := f.WriteResult(result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", result.FilePath, err)
+				hasErrors = true
+			} else if cfg.Write {
+				if cfg.Verbose {
+					fmt.Fprintf(os.Stderr, "Fixed: %s\n", result.FilePath)
 				}
 			}
 		}
 	}
 
+	// Consecutive file arguments are processed together, so that in package
+	// mode each package is processed once however many of its files are
+	// named.
+	var files []string
+	for _, path := range paths {
+		if isGoFile(path) {
+			files = append(files, path)
+			continue
+		}
+		if len(files) > 0 {
+			f.ProcessFilesFunc(files, handle)
+			files = nil
+		}
+		processPath(f, path, handle)
+	}
+	if len(files) > 0 {
+		f.ProcessFilesFunc(files, handle)
+	}
+
+	if reporter != nil {
+		if err := reporter.Flush(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			hasErrors = true
+		}
+	}
+
 	// Print summary
 	if cfg.Verbose {
 		fmt.Fprintf(os.Stderr, "\nTotal: %d violations in %d files\n", totalViolations, totalFixed)
+		if n := f.GeneratedSkipped(); n > 0 {
+			fmt.Fprintf(os.Stderr, "Skipped %d generated files\n", n)
+		}
 	}
 
 	if hasErrors {
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+	// Without --fix the run is a check; with --fix only --check fails it.
+	if (!cfg.Fix || flagCheck) && (totalViolations > 0 || totalFixed > 0) {
+		os.Exit(exitViolations)
+	}
+	os.Exit(exitClean)
+}
+
+// flagOverrides returns a function applying the rule flags set on the
+// command line to a Config, so that they take precedence over the
+// configuration files.
+func flagOverrides() func(*config.Config) {
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	return func(cfg *config.Config) {
+		if set["constructor"] || set["no-constructor"] {
+			cfg.CheckConstructor = flagConstructor && !flagNoConstructor
+		}
+		if set["exported"] || set["no-exported"] {
+			cfg.CheckExported = flagExported && !flagNoExported
+		}
+		if set["alphabetical"] {
+			cfg.Alphabetical = flagAlphabetical
+		}
+		if set["package"] {
+			cfg.Package = flagPackage
+		}
+		if set["move-methods"] {
+			cfg.MoveMethods = flagMoveMethods
+		}
+		if cfg.MoveMethods {
+			cfg.Package = true
+		}
+		if set["standalone-constructors"] {
+			cfg.StandaloneConstructors = flagStandalone
+		}
+		if set["constructors-before-methods"] {
+			cfg.ConstructorsBeforeMethods = flagBeforeMethods
+		}
+		if set["types"] {
+			cfg.TypeCheck = flagTypes
+		}
+		if set["verify-types"] {
+			cfg.VerifyTypes = flagVerifyTypes
+		}
+		if set["constructor-prefix"] {
+			cfg.ConstructorPrefixes = flagConstructorPrefixes
+		}
+		if set["constructor-pattern"] {
+			cfg.ConstructorPatterns = flagConstructorPatterns
+		}
+		if set["include-generated"] {
+			cfg.IncludeGenerated = flagGenerated
+		}
+		if set["exclude"] {
+			cfg.Exclude = append(cfg.Exclude, absPatterns(flagExclude)...)
+		}
+		if set["include"] {
+			cfg.Include = absPatterns(flagInclude)
+		}
+		if set["tags"] {
+			cfg.BuildTags = flagTags
+		}
+		if set["gitignore"] {
+			cfg.GitIgnore = flagGitIgnore
+		}
+		if set["j"] {
+			cfg.Jobs = flagJobs
+		}
+		if set["format"] {
+			cfg.Format = flagFormat
+		}
+	}
+}
+
+// absPatterns anchors patterns to the working directory, as the patterns of
+// a configuration file are anchored to its directory.
+func absPatterns(patterns []string) []string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return patterns
+	}
+	resolved := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		resolved[i] = config.ResolvePattern(pattern, wd)
+	}
+	return resolved
+}
+
+// configDir returns the directory whose configuration applies to path.
+func configDir(path string) string {
+	path = strings.TrimSuffix(path, "/...")
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return filepath.Dir(path)
+	}
+	return path
+}
+
+// useCache makes f use the result cache unless --no-cache is set. A cache
+// that cannot be set up only makes the run slower.
+func useCache(f *fixer.Fixer) {
+	if flagNoCache {
+		return
+	}
+	c, err := newCache()
+	if err != nil {
+		if flagVerbose {
+			fmt.Fprintf(os.Stderr, "Cache disabled: %v\n", err)
+		}
+		return
+	}
+	f.SetCache(c)
+}
+
+// newCache returns the result cache in the --cache-dir directory or the
+// default one.
+func newCache() (*cache.Cache, error) {
+	dir := flagCacheDir
+	if dir == "" {
+		var err error
+		if dir, err = cache.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return cache.New(dir, toolVersion()), nil
+}
+
+// toolVersion returns the version that keys the result cache. Development
+// builds all share a version, so the hash of the executable is added to keep
+// one build from reading the results of another.
+func toolVersion() string {
+	if Version != "dev" && !strings.HasSuffix(Version, "-dirty") {
+		return Version
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return Version
+	}
+	file, err := os.Open(exe)
+	if err != nil {
+		return Version
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return Version
+	}
+	return Version + "+" + hex.EncodeToString(h.Sum(nil))
+}
+
+// runCache runs the cache subcommand with args and returns the exit code.
+func runCache(args []string) int {
+	if len(args) != 1 || args[0] != "clean" {
+		fmt.Fprintln(os.Stderr, "Error: usage: funcorder-fix [--cache-dir dir] cache clean")
+		return exitError
+	}
+	c, err := newCache()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	if err := c.Clean(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	if flagVerbose {
+		fmt.Fprintf(os.Stderr, "Removed %s\n", c.Dir())
+	}
+	return exitClean
+}
+
+// runStdin fixes the Go source read from stdin and writes it to stdout, or
+// its diff with -d, and returns the exit code. The source is written
+// unchanged if there is nothing to fix, or if the --stdin-filename is
+// skipped by the exclude and include patterns or is generated, as when
+// walking directories. On errors nothing is written, so that editors keep
+// their buffer.
+func runStdin(loader *config.Loader) int {
+	src, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+		return exitError
+	}
+	name := flagStdinName
+	if name == "" {
+		name = "<standard input>"
+	}
+
+	resolved, err := loader.Load(filepath.Dir(name))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	cfg := resolved.Config
+
+	out := src
+	code := exitClean
+	if !skipSource(cfg, name, src) {
+		f := fixer.NewFixer(cfg)
+		f.SetConfigLoader(loader)
+		useCache(f)
+
+		result := f.ProcessSource(name, src)
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", name, result.Error)
+			return exitError
+		}
+		if cfg.Verbose {
+			printReport(result.Report)
+		}
+		if result.Fixed {
+			out = result.FixedContent
+		}
+		if flagCheck && (result.Violations > 0 || result.Fixed) {
+			code = exitViolations
+		}
+	}
+
+	if cfg.Diff {
+		fmt.Print(fixer.FormatDiff(name, src, out))
+	} else if _, err := os.Stdout.Write(out); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing stdout: %v\n", err)
+		return exitError
+	}
+	return code
+}
+
+// skipSource reports whether src, read for the file at path, is skipped by
+// the filters of directory walks.
+func skipSource(cfg *config.Config, path string, src []byte) bool {
+	if cfg.Excluded(path) || !cfg.Included(path) {
+		return true
+	}
+	if cfg.IncludeGenerated {
+		return false
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), path, src, parser.PackageClauseOnly|parser.ParseComments)
+	return err == nil && ast.IsGenerated(file)
+}
+
+// printConfig prints the effective configuration of path as YAML, preceded
+// by the configuration files it was merged from, and returns the exit code.
+func printConfig(loader *config.Loader, path string) int {
+	resolved, err := loader.Load(configDir(path))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	for _, file := range resolved.Files {
+		fmt.Printf("# %s\n", file)
+	}
+	if len(resolved.Files) == 0 {
+		fmt.Println("# no configuration files, using defaults")
+	}
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(config.FileOf(resolved.Config)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	return exitClean
+}
+
+// printViolations prints the violations of result in the text format.
+func printViolations(result *fixer.Result, cfg *config.Config) {
+	if cfg.List {
+		fmt.Println(result.FilePath)
+		return
+	}
+	if cfg.Verbose || !cfg.Fix {
+		printReport(result.Report)
+	}
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "%s: %d violations\n", result.FilePath, result.Violations)
 	}
 }
 
-// processPath processes a single path (file or directory).
-func processPath(f *fixer.Fixer, path string, cfg *config.Config) []*fixer.Result {
-	// Expand ... wildcard
-	if strings.HasSuffix(path, "/...") {
+// printReport prints every violation of report as file:line:col: message,
+// followed by the expected method order of each struct when requested.
+func printReport(report *detector.Report) {
+	if report == nil {
+		return
+	}
+	for _, v := range report.Violations {
+		fmt.Fprintln(os.Stderr, v)
+	}
+	if flagOrder {
+		for _, order := range report.Orders {
+			fmt.Fprintln(os.Stderr, order)
+		}
+	}
+}
+
+// isGoFile reports whether path names an existing .go file.
+func isGoFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && filepath.Ext(path) == ".go"
+}
+
+// processPath processes a directory or Go package pattern and calls handle
+// with each result. Go files are processed with Fixer.ProcessFilesFunc;
+// other files are ignored.
+func processPath(f *fixer.Fixer, path string, handle func(*fixer.Result)) {
+	// Resolve ... wildcards as go build does. Outside of a module the
+	// directory is walked instead.
+	if strings.Contains(path, "...") {
+		err := f.ProcessPatternsFunc("", []string{packagePattern(path)}, handle)
+		if err == nil {
+			return
+		}
 		dir := strings.TrimSuffix(path, "/...")
-		return f.ProcessDirectory(dir)
+		if info, statErr := os.Stat(dir); statErr == nil && info.IsDir() {
+			if flagVerbose {
+				fmt.Fprintf(os.Stderr, "Walking %s: %v\n", dir, err)
+			}
+			f.ProcessDirectoryFunc(dir, handle)
+			return
+		}
+		handle(&fixer.Result{
+			FilePath: path,
+			Error:    fmt.Errorf("cannot resolve pattern: %w", err),
+		})
+		return
 	}
 
 	// Check if it's a directory
 	info, err := os.Stat(path)
+	if os.IsNotExist(err) && !filepath.IsAbs(path) && !strings.HasPrefix(path, ".") {
+		// Not a file or directory, but possibly an import path.
+		if f.ProcessPatternsFunc("", []string{path}, handle) == nil {
+			return
+		}
+	}
 	if err != nil {
-		return []*fixer.Result{{
+		handle(&fixer.Result{
 			FilePath: path,
 			Error:    fmt.Errorf("cannot access path: %w", err),
-		}}
+		})
+		return
 	}
 
 	if info.IsDir() {
-		return f.ProcessDirectory(path)
+		f.ProcessDirectoryFunc(path, handle)
 	}
+}
 
-	// Single file
-	if filepath.Ext(path) == ".go" {
-		return []*fixer.Result{f.ProcessFile(path)}
+// packagePattern turns a pattern rooted in a relative directory, such as
+// internal/..., into ./internal/..., so that go list does not take it for an
+// import path.
+func packagePattern(pattern string) string {
+	if filepath.IsAbs(pattern) || strings.HasPrefix(pattern, ".") {
+		return pattern
 	}
-
-	return nil
+	dir, _, _ := strings.Cut(pattern, "...")
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		return pattern
+	}
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return "./" + pattern
+	}
+	return pattern
 }
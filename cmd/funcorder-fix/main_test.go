@@ -1,11 +1,16 @@
 package main
 
 import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/vajrock/funcorder-fix/report"
 )
 
 var binaryPath string
@@ -18,6 +23,9 @@ func TestMain(m *testing.M) {
 	}
 	defer os.RemoveAll(dir)
 
+	// Keep the result cache of the tests out of the user cache directory.
+	os.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+
 	binaryPath = filepath.Join(dir, "funcorder-fix")
 	cmd := exec.Command("go", "build", "-o", binaryPath, ".")
 	cmd.Stderr = os.Stderr
@@ -56,8 +64,8 @@ func TestCLI_DetectOnly(t *testing.T) {
 	if stdout != "" {
 		t.Errorf("expected empty stdout in detect mode, got %q", stdout)
 	}
-	if !strings.Contains(stderr, "violations") {
-		t.Errorf("expected stderr to mention violations, got %q", stderr)
+	if !strings.Contains(stderr, "exported_only.go:6:1: unexported method prepare should appear after exported method Start") {
+		t.Errorf("expected file:line:col diagnostics in stderr, got %q", stderr)
 	}
 }
 
@@ -105,6 +113,12 @@ func TestCLI_FixDiff(t *testing.T) {
 	if !strings.Contains(stdout, "---") || !strings.Contains(stdout, "+++") {
 		t.Errorf("expected diff markers in stdout, got %q", stdout)
 	}
+	if !strings.Contains(stdout, "\n@@ -") {
+		t.Errorf("expected a hunk header in stdout, got %q", stdout)
+	}
+	if strings.Contains(stdout, "package testpkg") {
+		t.Errorf("expected only changed lines and context, got the whole file: %q", stdout)
+	}
 }
 
 func TestCLI_ListMode(t *testing.T) {
@@ -157,8 +171,8 @@ func TestCLI_NoArgs(t *testing.T) {
 func TestCLI_NonExistentPath(t *testing.T) {
 	_, stderr, exitCode := runBinary(t, "/nonexistent/path")
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != 2 {
+		t.Errorf("expected exit code 2, got %d", exitCode)
 	}
 	if stderr == "" {
 		t.Error("expected stderr error message")
@@ -179,7 +193,7 @@ func TestCLI_WildcardDotDotDot(t *testing.T) {
 
 	_, stderr, _ := runBinary(t, dir+"/...")
 
-	if !strings.Contains(stderr, "violations") {
+	if !strings.Contains(stderr, filepath.Join("sub", "test.go")+":3:1: unexported method b") {
 		t.Errorf("expected violations via recursive wildcard, got stderr: %q", stderr)
 	}
 }
@@ -205,3 +219,695 @@ func TestCLI_DotSlashDotDotDot(t *testing.T) {
 		t.Errorf("expected stderr to mention violations when running ./... from dir, got stderr: %q", errBuf.String())
 	}
 }
+
+func TestCLI_PackageMode(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"svc.go":     "package p\n\ntype Svc struct{}\n\nfunc (s *Svc) Run() {}\n",
+		"helpers.go": "package p\n\nfunc (s *Svc) helper() {}\n\nfunc (s *Svc) Stop() {}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, stderr, _ := runBinary(t, "-v", dir)
+	if strings.Contains(stderr, "helpers.go") {
+		t.Errorf("expected no violations without --package, got stderr: %q", stderr)
+	}
+
+	_, _, exitCode := runBinary(t, "--fix", "-w", "--move-methods", dir)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", exitCode)
+	}
+
+	svc, err := os.ReadFile(filepath.Join(dir, "svc.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "package p\n\ntype Svc struct{}\n\nfunc (s *Svc) Run() {}\n\nfunc (s *Svc) Stop() {}\n\nfunc (s *Svc) helper() {}\n"
+	if string(svc) != want {
+		t.Errorf("svc.go after --move-methods:\n%s\nwant:\n%s", svc, want)
+	}
+
+	helpers, err := os.ReadFile(filepath.Join(dir, "helpers.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(helpers) != "package p\n" {
+		t.Errorf("expected helpers.go to keep only its package clause, got %q", helpers)
+	}
+}
+
+func TestCLI_StandaloneConstructors(t *testing.T) {
+	_, stderr, _ := runBinary(t, testdataPath("src", "standalone_constructors.go"))
+	if strings.Contains(stderr, "violations") {
+		t.Errorf("expected no violations by default, got stderr: %q", stderr)
+	}
+
+	stdout, _, _ := runBinary(t, "--fix", "--standalone-constructors", testdataPath("src", "standalone_constructors.go"))
+	golden, err := os.ReadFile(testdataPath("golden", "standalone_constructors.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimRight(stdout, "\n") != strings.TrimRight(string(golden), "\n") {
+		t.Errorf("expected fixed output to match golden file, got:\n%s", stdout)
+	}
+}
+
+func TestCLI_Types(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"order.go": `package shop
+
+type Order struct{ id int }
+
+func (o *Order) Origin() string { return "" }
+
+func (o *Order) Build() *Order { return o }
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(dir, "order.go")
+
+	_, stderr, _ := runBinary(t, "-v", path)
+	if !strings.Contains(stderr, "0 violations") {
+		t.Errorf("expected no violations by name, got stderr: %q", stderr)
+	}
+
+	_, stderr, _ = runBinary(t, "-v", "--types", path)
+	if !strings.Contains(stderr, "1 violations") {
+		t.Errorf("expected Build to be reported with --types, got stderr: %q", stderr)
+	}
+}
+
+func TestCLI_ConstructorNaming(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.go")
+	src := `package store
+
+type Store struct{}
+
+func (s *Store) Run() {}
+
+func (s *Store) OpenSession() *Store { return s }
+
+func (s *Store) OrDefault() *Store { return s }
+`
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// before reports whether a is declared before b in the fixed output.
+	before := func(stdout, a, b string) bool {
+		return strings.Index(stdout, a) < strings.Index(stdout, b)
+	}
+
+	stdout, _, _ := runBinary(t, "--fix", path)
+	if !before(stdout, "OrDefault", "Run") || !before(stdout, "Run", "OpenSession") {
+		t.Errorf("expected only OrDefault to move before Run by default, got:\n%s", stdout)
+	}
+
+	stdout, _, _ = runBinary(t, "--fix", "--constructor-prefix=New,Must,Open", path)
+	if !before(stdout, "OpenSession", "Run") || !before(stdout, "Run", "OrDefault") {
+		t.Errorf("expected only OpenSession to move before Run, got:\n%s", stdout)
+	}
+
+	stdout, _, _ = runBinary(t, "--fix", "--constructor-prefix=", "--constructor-pattern", "^Open[A-Z]", path)
+	if !before(stdout, "OpenSession", "Run") || !before(stdout, "Run", "OrDefault") {
+		t.Errorf("expected only OpenSession to move before Run with a pattern, got:\n%s", stdout)
+	}
+
+	_, stderr, exitCode := runBinary(t, "--constructor-pattern", "Open(", path)
+	if exitCode == 0 || !strings.Contains(stderr, "invalid constructor pattern") {
+		t.Errorf("expected an invalid pattern error, got exit code %d, stderr: %q", exitCode, stderr)
+	}
+}
+
+func TestCLI_ExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	src, err := os.ReadFile(testdataPath("src", "exported_only.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpFile := filepath.Join(dir, "exported_only.go")
+	if err := os.WriteFile(tmpFile, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"clean", []string{testdataPath("src", "no_violations.go")}, 0},
+		{"violations", []string{testdataPath("src", "exported_only.go")}, 1},
+		{"list", []string{"-l", testdataPath("src", "exported_only.go")}, 1},
+		{"fix", []string{"--fix", testdataPath("src", "exported_only.go")}, 0},
+		{"fix check", []string{"--fix", "--check", testdataPath("src", "exported_only.go")}, 1},
+		{"fix diff exit-code", []string{"--fix", "-d", "--exit-code", testdataPath("src", "exported_only.go")}, 1},
+		{"fix check clean", []string{"--fix", "--check", testdataPath("golden", "exported_only.go")}, 0},
+		{"write check", []string{"--fix", "-w", "--check", tmpFile}, 1},
+		{"write check after fix", []string{"--fix", "-w", "--check", tmpFile}, 0},
+		{"error", []string{"/nonexistent/path"}, 2},
+		{"invalid pattern", []string{"--constructor-pattern", "(", tmpFile}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, exitCode := runBinary(t, tt.args...)
+			if exitCode != tt.want {
+				t.Errorf("expected exit code %d, got %d (stderr: %q)", tt.want, exitCode, stderr)
+			}
+		})
+	}
+}
+
+func TestCLI_ExpectedOrder(t *testing.T) {
+	path := testdataPath("src", "mixed_violations.go")
+
+	_, stderr, _ := runBinary(t, path)
+	if strings.Contains(stderr, "expected method order") {
+		t.Errorf("expected no method order without --expected-order, got %q", stderr)
+	}
+
+	_, stderr, _ = runBinary(t, "--expected-order", path)
+	want := "mixed_violations.go:5:1: expected method order for Engine: NewInstance, Run, Stop, warmUp, status"
+	if !strings.Contains(stderr, want) {
+		t.Errorf("expected %q in stderr, got %q", want, stderr)
+	}
+}
+
+func TestCLI_FormatJSON(t *testing.T) {
+	stdout, stderr, exitCode := runBinary(t, "--format=json",
+		testdataPath("src", "mixed_violations.go"), testdataPath("src", "no_violations.go"))
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if stderr != "" {
+		t.Errorf("expected no text output on stderr, got %q", stderr)
+	}
+
+	var files []report.File
+	if err := json.Unmarshal([]byte(stdout), &files); err != nil {
+		t.Fatalf("stdout is not valid JSON: %v\n%s", err, stdout)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if len(files[0].Violations) != 3 || len(files[0].Moves) == 0 || files[0].Fixed {
+		t.Errorf("unexpected report for mixed_violations.go: %+v", files[0])
+	}
+	if len(files[1].Violations) != 0 {
+		t.Errorf("expected no violations for no_violations.go, got %+v", files[1].Violations)
+	}
+
+	// With --fix the fixed content must not be mixed into the report.
+	stdout, _, _ = runBinary(t, "--format=json", "--fix", testdataPath("src", "mixed_violations.go"))
+	if err := json.Unmarshal([]byte(stdout), &files); err != nil {
+		t.Fatalf("stdout is not valid JSON with --fix: %v\n%s", err, stdout)
+	}
+	if len(files) != 1 || !files[0].Fixed {
+		t.Errorf("expected the file to be reported as fixed, got %+v", files)
+	}
+}
+
+func TestCLI_FormatSARIF(t *testing.T) {
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string            `json:"ruleId"`
+				Fixes  []json.RawMessage `json:"fixes"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	stdout, stderr, exitCode := runBinary(t, "--format=sarif", testdataPath("src", "mixed_violations.go"))
+	if exitCode != 1 || stderr != "" {
+		t.Errorf("expected exit code 1 and no stderr, got %d, %q", exitCode, stderr)
+	}
+	if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+		t.Fatalf("stdout is not valid JSON: %v\n%s", err, stdout)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 3 {
+		t.Fatalf("expected a SARIF 2.1.0 run with 3 results, got %s", stdout)
+	}
+	for _, res := range log.Runs[0].Results {
+		if len(res.Fixes) != 0 {
+			t.Errorf("expected no fixes without --fix, got %d for %s", len(res.Fixes), res.RuleID)
+		}
+	}
+
+	stdout, _, _ = runBinary(t, "--format=sarif", "--fix", testdataPath("src", "mixed_violations.go"))
+	if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+		t.Fatalf("stdout is not valid JSON with --fix: %v\n%s", err, stdout)
+	}
+	for _, res := range log.Runs[0].Results {
+		if len(res.Fixes) != 1 {
+			t.Errorf("expected a fix for %s with --fix, got %d", res.RuleID, len(res.Fixes))
+		}
+	}
+}
+
+func TestCLI_FormatXML(t *testing.T) {
+	stdout, stderr, exitCode := runBinary(t, "--format=checkstyle",
+		testdataPath("src", "mixed_violations.go"), testdataPath("src", "no_violations.go"))
+	if exitCode != 1 || stderr != "" {
+		t.Errorf("expected exit code 1 and no stderr, got %d, %q", exitCode, stderr)
+	}
+	var checkstyle struct {
+		Files []struct {
+			Errors []struct{} `xml:"error"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal([]byte(stdout), &checkstyle); err != nil {
+		t.Fatalf("stdout is not valid XML: %v\n%s", err, stdout)
+	}
+	if len(checkstyle.Files) != 2 || len(checkstyle.Files[0].Errors) != 3 || len(checkstyle.Files[1].Errors) != 0 {
+		t.Errorf("unexpected checkstyle report:\n%s", stdout)
+	}
+
+	stdout, _, _ = runBinary(t, "--format=junit", testdataPath("src", "multi_struct.go"), testdataPath("src", "no_violations.go"))
+	var junit struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+	}
+	if err := xml.Unmarshal([]byte(stdout), &junit); err != nil {
+		t.Fatalf("stdout is not valid XML: %v\n%s", err, stdout)
+	}
+	if junit.Failures != 2 || junit.Tests <= junit.Failures {
+		t.Errorf("expected passing and failing test cases, got tests=%d failures=%d", junit.Tests, junit.Failures)
+	}
+}
+
+func TestCLI_FormatCI(t *testing.T) {
+	stdout, stderr, exitCode := runBinary(t, "--format=github", testdataPath("src", "multi_struct.go"))
+	if exitCode != 1 || stderr != "" {
+		t.Errorf("expected exit code 1 and no stderr, got %d, %q", exitCode, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "::error file=") || !strings.Contains(lines[0], ",line=7,col=1::") {
+		t.Errorf("unexpected workflow commands:\n%s", stdout)
+	}
+
+	stdout, _, _ = runBinary(t, "--format=gitlab", testdataPath("src", "multi_struct.go"))
+	var issues []struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &issues); err != nil {
+		t.Fatalf("stdout is not valid JSON: %v\n%s", err, stdout)
+	}
+	if len(issues) != 2 || issues[0].Fingerprint == "" {
+		t.Errorf("unexpected Code Quality report:\n%s", stdout)
+	}
+}
+
+func TestCLI_FormatUnknown(t *testing.T) {
+	_, stderr, exitCode := runBinary(t, "--format=yaml", testdataPath("src", "no_violations.go"))
+	if exitCode != 2 || !strings.Contains(stderr, "unknown format") {
+		t.Errorf("expected exit code 2 and an unknown format error, got %d, %q", exitCode, stderr)
+	}
+}
+
+func TestCLI_ConfigFile(t *testing.T) {
+	const src = "package p\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".funcorder-fix.yaml":     "rules:\n  exported: false\n",
+		"a.go":                    src,
+		"sub/.funcorder-fix.yaml": "rules:\n  exported: true\nformat: json\n",
+		"sub/a.go":                src,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The nested file enables the rule the top-level file disables.
+	_, stderr, exitCode := runBinary(t, dir+"/...")
+	if exitCode != 1 || !strings.Contains(stderr, filepath.Join("sub", "a.go")+":5:1:") ||
+		strings.Contains(stderr, filepath.Join(dir, "a.go")+":") {
+		t.Errorf("expected a violation only in sub/a.go, got %d, %q", exitCode, stderr)
+	}
+
+	// Explicit flags override the files.
+	if _, stderr, exitCode := runBinary(t, "--no-exported", dir+"/..."); exitCode != 0 {
+		t.Errorf("expected --no-exported to win, got %d, %q", exitCode, stderr)
+	}
+
+	// The format of the first path is used for the whole run.
+	stdout, _, _ := runBinary(t, filepath.Join(dir, "sub"))
+	var files []report.File
+	if err := json.Unmarshal([]byte(stdout), &files); err != nil {
+		t.Errorf("expected JSON output from the config file format: %v\n%s", err, stdout)
+	}
+
+	stdout, stderr, exitCode = runBinary(t, "config", filepath.Join(dir, "sub"))
+	if exitCode != 0 {
+		t.Fatalf("config: expected exit code 0, got %d: %s", exitCode, stderr)
+	}
+	for _, want := range []string{
+		"# " + filepath.Join(dir, ".funcorder-fix.yaml"),
+		"# " + filepath.Join(dir, "sub", ".funcorder-fix.yaml"),
+		"exported: true",
+		"format: json",
+		"- New",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("config: expected %q in output:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, _ = runBinary(t, "--no-exported", "config", dir)
+	if !strings.Contains(stdout, "exported: false") || !strings.Contains(stdout, "format: text") {
+		t.Errorf("config: unexpected output for the top directory:\n%s", stdout)
+	}
+}
+
+func TestCLI_Alphabetical(t *testing.T) {
+	path := testdataPath("src", "alphabetical.go")
+	if _, stderr, exitCode := runBinary(t, path); exitCode != 0 {
+		t.Errorf("expected no violations by default, got %d: %q", exitCode, stderr)
+	}
+
+	_, stderr, exitCode := runBinary(t, "--alphabetical", path)
+	if exitCode != 1 || !strings.Contains(stderr, "exported method Get should appear before exported method Set in alphabetical order") {
+		t.Errorf("expected alphabetical violations, got %d: %q", exitCode, stderr)
+	}
+
+	stdout, _, _ := runBinary(t, "--alphabetical", "--fix", path)
+	golden, err := os.ReadFile(testdataPath("golden", "alphabetical.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(stdout) != strings.TrimSpace(string(golden)) {
+		t.Errorf("fixed output does not match the golden file:\n%s", stdout)
+	}
+}
+
+func TestCLI_NoIgnore(t *testing.T) {
+	path := testdataPath("src", "ignore.go")
+	_, stderr, exitCode := runBinary(t, path)
+	if exitCode != 1 || strings.Contains(stderr, "init") || strings.Contains(stderr, "parse") {
+		t.Errorf("expected suppressed methods to be skipped, got %d: %q", exitCode, stderr)
+	}
+
+	_, stderr, _ = runBinary(t, "--no-ignore", path)
+	for _, name := range []string{"listen", "init", "parse"} {
+		if !strings.Contains(stderr, "unexported method "+name) {
+			t.Errorf("expected --no-ignore to report %s, got %q", name, stderr)
+		}
+	}
+}
+
+func TestCLI_IncludeGenerated(t *testing.T) {
+	const src = "package p\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mock.go")
+	if err := os.WriteFile(path, []byte("// Code generated by mockgen. DO NOT EDIT.\n\n"+src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, exitCode := runBinary(t, "-v", dir+"/...")
+	if exitCode != 0 || !strings.Contains(stderr, "Skipped 1 generated files") {
+		t.Errorf("expected the generated file to be skipped, got %d: %q", exitCode, stderr)
+	}
+
+	if _, stderr, exitCode := runBinary(t, "--include-generated", dir+"/..."); exitCode != 1 {
+		t.Errorf("expected --include-generated to check the file, got %d: %q", exitCode, stderr)
+	}
+}
+
+func TestCLI_ExcludeInclude(t *testing.T) {
+	const src = "package p\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".gitignore":         "out/\n",
+		"a.go":               src,
+		"third_party/lib.go": src,
+		"out/gen.go":         src,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, stderr, _ := runBinary(t, "--exclude", "third_party", "--gitignore", dir+"/...")
+	if !strings.Contains(stderr, "a.go:") || strings.Contains(stderr, "lib.go") || strings.Contains(stderr, "gen.go") {
+		t.Errorf("expected only a.go to be checked, got %q", stderr)
+	}
+
+	// The name of a directory above the walked one does not exclude it.
+	_, stderr, _ = runBinary(t, "--exclude", filepath.Base(filepath.Dir(dir)), dir)
+	if !strings.Contains(stderr, "a.go:") || !strings.Contains(stderr, "lib.go") {
+		t.Errorf("expected the parent directory name to exclude nothing, got %q", stderr)
+	}
+
+	_, stderr, _ = runBinary(t, "--include", "lib.go", "--include", "gen.go", dir+"/...")
+	if strings.Contains(stderr, "a.go:") || !strings.Contains(stderr, "lib.go") || !strings.Contains(stderr, "gen.go") {
+		t.Errorf("expected only the included files to be checked, got %q", stderr)
+	}
+}
+
+func TestCLI_PackagePatterns(t *testing.T) {
+	const src = "package %s\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":            "module example.com/shop\n\ngo 1.21\n",
+		"shop.go":           "package shop\n",
+		"internal/a/a.go":   fmt.Sprintf(src, "a"),
+		"internal/b/b.go":   "//go:build integration\n\n" + fmt.Sprintf(src, "b"),
+		"tools/go.mod":      "module example.com/shop/tools\n\ngo 1.21\n",
+		"tools/tool/one.go": fmt.Sprintf(src, "tool"),
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(args ...string) string {
+		cmd := exec.Command(binaryPath, args...)
+		cmd.Dir = dir
+		var errBuf strings.Builder
+		cmd.Stderr = &errBuf
+		_ = cmd.Run()
+		return errBuf.String()
+	}
+
+	// The nested tools module and the file behind a build tag are skipped.
+	stderr := run("internal/...")
+	if !strings.Contains(stderr, "a.go:") || strings.Contains(stderr, "b.go:") {
+		t.Errorf("expected only internal/a to be checked, got %q", stderr)
+	}
+	if stderr := run("./..."); strings.Contains(stderr, "one.go:") {
+		t.Errorf("expected the nested module to be skipped, got %q", stderr)
+	}
+
+	if stderr := run("--tags", "integration", "./internal/..."); !strings.Contains(stderr, "b.go:") {
+		t.Errorf("expected --tags to include internal/b, got %q", stderr)
+	}
+	if stderr := run("example.com/shop/internal/a"); !strings.Contains(stderr, "a.go:") {
+		t.Errorf("expected an import path to be resolved, got %q", stderr)
+	}
+}
+
+func TestCLI_PackagePatternPaths(t *testing.T) {
+	const src = "package %s\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":          "module example.com/shop\n\ngo 1.21\n",
+		"shop.go":         fmt.Sprintf(src, "shop"),
+		"internal/a/a.go": fmt.Sprintf(src, "a"),
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list := func(pattern string) string {
+		cmd := exec.Command(binaryPath, "-l", pattern)
+		cmd.Dir = dir
+		var outBuf strings.Builder
+		cmd.Stdout = &outBuf
+		_ = cmd.Run()
+		return outBuf.String()
+	}
+
+	// Resolved packages are named relative to the working directory, like
+	// the files of a walk.
+	walked := list(".")
+	if want := filepath.Join("internal", "a", "a.go") + "\nshop.go\n"; walked != want {
+		t.Fatalf("-l . printed %q, want %q", walked, want)
+	}
+	if resolved := list("./..."); resolved != walked {
+		t.Errorf("-l ./... printed %q, want %q", resolved, walked)
+	}
+}
+
+func TestCLI_Jobs(t *testing.T) {
+	dir := testdataPath("src")
+	_, serial, _ := runBinary(t, "-j", "1", dir)
+	for _, jobs := range []string{"4", "0"} {
+		if _, stderr, _ := runBinary(t, "-j", jobs, dir); stderr != serial {
+			t.Errorf("-j %s output differs from -j 1:\n%s\nwant:\n%s", jobs, stderr, serial)
+		}
+	}
+}
+
+func TestCLI_Cache(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	file := testdataPath("src", "exported_only.go")
+	entries := func() int {
+		matches, _ := filepath.Glob(filepath.Join(cacheDir, "*", "*.json"))
+		return len(matches)
+	}
+
+	_, uncached, code := runBinary(t, "--no-cache", "--cache-dir", cacheDir, file)
+	if code != exitViolations || entries() != 0 {
+		t.Fatalf("expected --no-cache to check without caching, got exit %d, %d entries", code, entries())
+	}
+
+	for run := 0; run < 2; run++ {
+		_, stderr, code := runBinary(t, "--cache-dir", cacheDir, file)
+		if code != exitViolations || stderr != uncached {
+			t.Errorf("run %d: expected the uncached output, got exit %d:\n%s", run, code, stderr)
+		}
+	}
+	if entries() != 1 {
+		t.Errorf("expected 1 cache entry, got %d", entries())
+	}
+
+	if _, stderr, code := runBinary(t, "--cache-dir", cacheDir, "cache", "clean"); code != exitClean {
+		t.Fatalf("cache clean failed with exit %d: %s", code, stderr)
+	}
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Errorf("expected cache clean to remove %s, got %v", cacheDir, err)
+	}
+
+	if _, _, code := runBinary(t, "cache", "purge"); code != exitError {
+		t.Errorf("expected exit %d for an unknown cache command, got %d", exitError, code)
+	}
+}
+
+func TestCLI_Stdin(t *testing.T) {
+	run := func(input string, args ...string) (stdout, stderr string, exitCode int) {
+		t.Helper()
+		cmd := exec.Command(binaryPath, append([]string{"--no-cache"}, args...)...)
+		cmd.Stdin = strings.NewReader(input)
+		var outBuf, errBuf strings.Builder
+		cmd.Stdout = &outBuf
+		cmd.Stderr = &errBuf
+		err := cmd.Run()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatalf("failed to run binary: %v", err)
+		}
+		return outBuf.String(), errBuf.String(), exitCode
+	}
+	read := func(parts ...string) string {
+		t.Helper()
+		data, err := os.ReadFile(testdataPath(parts...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	src, golden := read("src", "exported_only.go"), read("golden", "exported_only.go")
+
+	// The fixed source is written to stdout, the input unchanged if there
+	// is nothing to fix.
+	for _, tt := range []struct{ input, want string }{{src, golden}, {golden, golden}} {
+		stdout, stderr, code := run(tt.input, "--stdin")
+		if code != exitClean || stdout != tt.want {
+			t.Errorf("expected exit 0 and the fixed source, got %d:\n%s\nstderr: %s", code, stdout, stderr)
+		}
+	}
+	if stdout, _, code := run(src, "--stdin", "--check"); code != exitViolations || stdout != golden {
+		t.Errorf("expected exit 1 with --check and the fixed source, got %d:\n%s", code, stdout)
+	}
+	if stdout, stderr, code := run("package p\n\nfunc (", "--stdin"); code != exitError || stdout != "" ||
+		!strings.Contains(stderr, "<standard input>") {
+		t.Errorf("expected exit 2 and no output for invalid source, got %d, %q, %q", code, stdout, stderr)
+	}
+
+	// The file name locates the configuration and is matched against its
+	// exclude patterns.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".funcorder-fix.yaml"), []byte("exclude: [\"*_gen.go\"]\nrules:\n  exported: false\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"worker.go", "worker_gen.go"} {
+		stdout, stderr, code := run(src, "--stdin-filename", filepath.Join(dir, name))
+		if code != exitClean || stdout != src {
+			t.Errorf("%s: expected the input unchanged, got %d:\n%s\nstderr: %s", name, code, stdout, stderr)
+		}
+	}
+	if stdout, _, _ := run(src, "--stdin-filename", filepath.Join(dir, "worker.go"), "--exported"); stdout != golden {
+		t.Errorf("expected flags to override the configuration, got:\n%s", stdout)
+	}
+}
+
+func TestCLI_PackageFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"service.go": "package p\n\ntype Service struct{}\n\nfunc (s *Service) b() {}\n\nfunc (s *Service) A() {}\n",
+		"helpers.go": "package p\n\nfunc (s *Service) c() {}\n\nfunc (s *Service) C() {}\n",
+		"other.go":   "package p\n\ntype Other struct{}\n\nfunc (o *Other) d() {}\n\nfunc (o *Other) D() {}\n",
+		"mock_gen.go": "// Code generated by mockgen. DO NOT EDIT.\n\npackage p\n\n" +
+			"func (o *Other) e() {}\n\nfunc (o *Other) E() {}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	service, helpers := filepath.Join(dir, "service.go"), filepath.Join(dir, "helpers.go")
+
+	// Every file of the package is seen once, and only the named files
+	// are reported.
+	stdout, _, code := runBinary(t, "--no-cache", "--package", "-l", service, helpers, service)
+	want := service + "\n" + helpers + "\n"
+	if code != exitViolations || stdout != want {
+		t.Errorf("expected each named file listed once, got %d:\n%s\nwant:\n%s", code, stdout, want)
+	}
+	stdout, _, _ = runBinary(t, "--no-cache", "--package", "--format=json", service, helpers)
+	if strings.Count(stdout, `"path"`) != 2 || strings.Contains(stdout, "other.go") || strings.Contains(stdout, "mock_gen.go") {
+		t.Errorf("expected reports for the 2 named files only, got:\n%s", stdout)
+	}
+
+	// The other files are filtered like those of a directory walk.
+	if _, stderr, _ := runBinary(t, "--no-cache", "--package", "-v", service); !strings.Contains(stderr, "Skipped 1 generated files") {
+		t.Errorf("expected mock_gen.go to be skipped, got %s", stderr)
+	}
+
+	// Moving a method out of a file that was not named is refused.
+	_, stderr, code := runBinary(t, "--no-cache", "--move-methods", "--fix", "-w", service)
+	if code != exitError || !strings.Contains(stderr, "not named") {
+		t.Errorf("expected the fix to be refused, got %d: %s", code, stderr)
+	}
+	if content, _ := os.ReadFile(service); strings.Contains(string(content), "C()") {
+		t.Errorf("expected service.go to be left unchanged, got:\n%s", content)
+	}
+}
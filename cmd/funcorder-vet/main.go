@@ -0,0 +1,16 @@
+// Command funcorder-vet runs the funcorderfix analyzer under go vet.
+//
+// Usage:
+//
+//	go vet -vettool=$(which funcorder-vet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/vajrock/funcorder-fix/analyzer"
+)
+
+func main() {
+	unitchecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,159 @@
+// Package cache stores the results of processing files on disk, so that
+// repeated runs over unchanged files can skip the work.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Cache is an on-disk store of JSON-encoded entries addressed by key. It is
+// safe for concurrent use; entries are written atomically.
+type Cache struct {
+	dir     string
+	version string
+}
+
+// New returns a Cache storing its entries in dir. version identifies the
+// tool build and is part of every key, so that a new build never reads the
+// entries of another.
+func New(dir, version string) *Cache {
+	return &Cache{dir: dir, version: version}
+}
+
+// DefaultDir returns the default cache directory, funcorder-fix in the user
+// cache directory.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "funcorder-fix"), nil
+}
+
+// Dir returns the directory the entries are stored in.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Key returns the key of the entry for parts, typically the configuration,
+// the path and the content of a file. Parts are length-prefixed, so
+// different splits of the same bytes give different keys.
+func (c *Cache) Key(parts ...[]byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%s", len(c.version), c.version)
+	for _, part := range parts {
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get decodes the entry for key into v and reports whether it was found.
+// Unreadable or corrupt entries are treated as missing.
+func (c *Cache) Get(key string, v any) bool {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Put stores v as the entry for key.
+func (c *Cache) Put(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Clean removes every entry, and the cache directory if nothing else is left
+// in it. Other files are kept, so that pointing the cache at the wrong
+// directory cannot delete it.
+func (c *Cache) Clean() error {
+	dirs, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		if !d.IsDir() || !isShard(d.Name()) {
+			continue
+		}
+		if err := c.cleanShard(filepath.Join(c.dir, d.Name())); err != nil {
+			return err
+		}
+	}
+	// A directory that is not empty is kept.
+	_ = os.Remove(c.dir)
+	return nil
+}
+
+// cleanShard removes the entries, and temporary files of interrupted writes,
+// in the subdirectory dir, and dir itself if it is left empty.
+func (c *Cache) cleanShard(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || (filepath.Ext(name) != ".json" && filepath.Ext(name) != ".tmp") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	_ = os.Remove(dir)
+	return nil
+}
+
+// path returns the file holding the entry for key. Entries are spread over
+// subdirectories named after the first two characters of their key.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// isShard reports whether name is the name of an entry subdirectory: two
+// lowercase hexadecimal digits.
+func isShard(name string) bool {
+	if len(name) != 2 {
+		return false
+	}
+	for _, r := range name {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
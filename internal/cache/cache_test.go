@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	c := New(dir, "v1")
+
+	type entry struct {
+		Violations int
+		Content    []byte
+	}
+
+	key := c.Key([]byte("config"), []byte("a.go"), []byte("package p"))
+	var got entry
+	if c.Get(key, &got) {
+		t.Fatal("expected a miss on an empty cache")
+	}
+
+	want := entry{Violations: 2, Content: []byte("package p\n")}
+	if err := c.Put(key, want); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Get(key, &got) || got.Violations != want.Violations || string(got.Content) != string(want.Content) {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+
+	// The version and every part take part in the key.
+	if New(dir, "v2").Key([]byte("config"), []byte("a.go"), []byte("package p")) == key {
+		t.Error("expected the version to change the key")
+	}
+	if c.Key([]byte("configa.go"), []byte("package p")) == key {
+		t.Error("expected parts to be length-prefixed")
+	}
+
+	// Corrupt entries are misses.
+	if err := os.WriteFile(c.path(key), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if c.Get(key, &got) {
+		t.Error("expected a corrupt entry to be a miss")
+	}
+
+	if err := c.Clean(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected Clean to remove %s, got %v", dir, err)
+	}
+	if err := c.Clean(); err != nil {
+		t.Errorf("expected Clean of a missing directory to succeed, got %v", err)
+	}
+}
+
+func TestCache_CleanKeepsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	other := filepath.Join(dir, "ab", "notes.txt")
+	if err := os.MkdirAll(filepath.Dir(other), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(other, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(dir, "v1")
+	key := c.Key([]byte("a"))
+	if err := c.Put(key, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Clean(); err != nil {
+		t.Fatal(err)
+	}
+
+	var v int
+	if c.Get(key, &v) {
+		t.Error("expected the entry to be removed")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("expected %s to be kept, got %v", other, err)
+	}
+}
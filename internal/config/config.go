@@ -1,6 +1,14 @@
 // Package config provides configuration types for the funcorder-fix tool.
 package config
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
 // Config holds the configuration for the funcorder-fix tool.
 type Config struct {
 	// Fix enables automatic fixing of violations.
@@ -18,13 +26,94 @@ type Config struct {
 	// Verbose enables verbose output.
 	Verbose bool
 
-	// CheckConstructor enables checking that constructors (New*, Must*, Or*)
-	// appear after struct definition.
+	// CheckConstructor enables checking that constructors (by default New*,
+	// Must*, Or*) appear after struct definition.
 	CheckConstructor bool
 
+	// ConstructorPrefixes lists the name prefixes that mark a method as a
+	// constructor. Defaults to New, Must and Or.
+	ConstructorPrefixes []string
+
+	// ConstructorPatterns lists regular expressions matched against method
+	// names. A method matching any of them is a constructor as well.
+	ConstructorPatterns []string
+
 	// CheckExported enables checking that exported methods appear before
 	// unexported methods.
 	CheckExported bool
+
+	// Alphabetical enables checking that the methods of each group
+	// (constructors, exported, unexported) are sorted by name, and makes the
+	// fixer sort them.
+	Alphabetical bool
+
+	// Package analyzes all files of a package together, so methods declared
+	// in a different file than their struct are checked as well.
+	Package bool
+
+	// MoveMethods reports methods declared outside the file of their struct
+	// and moves them into that file. Only used in package mode.
+	MoveMethods bool
+
+	// StandaloneConstructors enables checking that constructor functions
+	// without a receiver (func NewFoo() *Foo) are placed directly after the
+	// declaration of the struct they return.
+	StandaloneConstructors bool
+
+	// ConstructorsBeforeMethods relaxes StandaloneConstructors to the rule
+	// of upstream funcorder: a constructor only has to follow the
+	// declaration of its struct and precede the methods of the struct.
+	ConstructorsBeforeMethods bool
+
+	// TypeCheck type-checks each package and classifies constructors by the
+	// type they return instead of by name prefix. Files that fail to
+	// type-check fall back to the name-based rule.
+	TypeCheck bool
+
+	// VerifyTypes type-checks each fixed package before and after the fix,
+	// with the fixed files overlaid rather than written, and rejects fixes
+	// that introduce type errors or change the objects of the package.
+	VerifyTypes bool
+
+	// NoIgnore disables the //nolint:funcorder and //funcorder:ignore
+	// directives, so that suppressed structs and methods are checked and
+	// fixed like any other.
+	NoIgnore bool
+
+	// IncludeGenerated processes generated files (marked with a
+	// "// Code generated ... DO NOT EDIT." comment) when walking
+	// directories. They are skipped by default.
+	IncludeGenerated bool
+
+	// Exclude lists glob patterns of files and directories to skip when
+	// walking directories. Patterns containing a path separator are matched
+	// against the whole path. A pattern of the form base/**/glob, which
+	// ResolvePattern makes of the other patterns, matches glob against the
+	// path elements below base; a bare glob is matched against those below
+	// the working directory.
+	Exclude []string
+
+	// Include lists glob patterns, matched like Exclude, that restrict the
+	// files processed when walking directories to those matching one of
+	// them. All files are processed when it is empty.
+	Include []string
+
+	// GitIgnore skips the files and directories ignored by the .gitignore
+	// files found in the walked directories and their parents, up to the
+	// root of the git repository.
+	GitIgnore bool
+
+	// BuildTags lists the build tags used when resolving package patterns and
+	// type-checking, as with go build -tags. GOOS, GOARCH and go.work are
+	// taken from the environment.
+	BuildTags []string
+
+	// Jobs is the number of files or packages processed concurrently. Zero
+	// or less means GOMAXPROCS.
+	Jobs int
+
+	// Format is the output format: text or one of the report formats.
+	Format string
 }
 
 // DefaultConfig returns a Config with default settings.
@@ -37,7 +126,144 @@ func DefaultConfig() *Config {
 		Verbose:          false,
 		CheckConstructor: true,
 		CheckExported:    true,
+		Alphabetical:     false,
+		Package:          false,
+		MoveMethods:      false,
+
+		ConstructorPrefixes:       []string{"New", "Must", "Or"},
+		ConstructorPatterns:       nil,
+		StandaloneConstructors:    false,
+		ConstructorsBeforeMethods: false,
+		TypeCheck:                 false,
+		VerifyTypes:               false,
+		NoIgnore:                  false,
+		IncludeGenerated:          false,
+		Exclude:                   nil,
+		Include:                   nil,
+		GitIgnore:                 false,
+		BuildTags:                 nil,
+		Jobs:                      0,
+		Format:                    "text",
+	}
+}
+
+// Validate reports settings that cannot be used, such as constructor
+// patterns that are not valid regular expressions or malformed exclude and
+// include patterns.
+func (c *Config) Validate() error {
+	for _, pattern := range c.ConstructorPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid constructor pattern %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range c.Exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range c.Include {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Excluded reports whether path matches one of the Exclude patterns.
+func (c *Config) Excluded(path string) bool {
+	return matchAny(c.Exclude, path)
+}
+
+// Included reports whether path matches one of the Include patterns, or
+// whether there are none.
+func (c *Config) Included(path string) bool {
+	return len(c.Include) == 0 || matchAny(c.Include, path)
+}
+
+// ResolvePattern anchors pattern, read from a file or the command line, to
+// dir. A pattern containing a slash is made relative to dir; other patterns
+// become dir/**/pattern, so that they only match the names of the files and
+// directories below dir, never those of dir and its parents.
+func ResolvePattern(pattern, dir string) string {
+	if strings.Contains(pattern, "/") {
+		return filepath.Join(dir, filepath.FromSlash(pattern))
+	}
+	return filepath.Join(dir, "**", pattern)
+}
+
+// matchAny reports whether path matches one of patterns. Patterns of the
+// form base/**/glob, and bare globs with the working directory as base, are
+// matched against every element of path below base, or against the name of
+// path if it is not below base. Other patterns are matched against the
+// absolute path and each of its parent directories.
+func matchAny(patterns []string, path string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	for _, pattern := range patterns {
+		if base, glob, ok := splitElemPattern(pattern); ok {
+			if matchBelow(base, glob, abs) {
+				return true
+			}
+			continue
+		}
+		// Match the path and each of its parent directories.
+		for p := abs; ; p = filepath.Dir(p) {
+			if ok, _ := filepath.Match(pattern, p); ok {
+				return true
+			}
+			if filepath.Dir(p) == p {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// splitElemPattern splits a pattern matched against path elements into its
+// base directory and glob. It returns false for patterns matched against the
+// whole path.
+func splitElemPattern(pattern string) (base, glob string, ok bool) {
+	sep := string(filepath.Separator)
+	if !strings.Contains(pattern, sep) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", "", false
+		}
+		return wd, pattern, true
+	}
+	i := strings.LastIndex(pattern, sep+"**"+sep)
+	if i < 0 || strings.Contains(pattern[i+4:], sep) {
+		return "", "", false
+	}
+	base = pattern[:i]
+	if base == "" || strings.HasSuffix(base, ":") {
+		base += sep
 	}
+	return base, pattern[i+4:], true
+}
+
+// matchBelow reports whether glob matches one of the elements of abs below
+// base, or the name of abs if it is not below base.
+func matchBelow(base, glob, abs string) bool {
+	rel, err := filepath.Rel(base, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		ok, _ := filepath.Match(glob, filepath.Base(abs))
+		return ok
+	}
+	if rel == "." {
+		return false
+	}
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		if ok, _ := filepath.Match(glob, elem); ok {
+			return true
+		}
+	}
+	return false
 }
 
 // ViolationType represents the type of funcorder violation.
@@ -49,6 +275,14 @@ const (
 
 	// ViolationExported indicates unexported method appears before exported.
 	ViolationExported
+
+	// ViolationMisplaced indicates a method is declared in a different file
+	// than its struct.
+	ViolationMisplaced
+
+	// ViolationAlphabetical indicates a method is not sorted by name within
+	// its group.
+	ViolationAlphabetical
 )
 
 // String returns a human-readable description of the violation type.
@@ -58,7 +292,28 @@ func (v ViolationType) String() string {
 		return "constructor ordering"
 	case ViolationExported:
 		return "exported before unexported"
+	case ViolationMisplaced:
+		return "method outside struct file"
+	case ViolationAlphabetical:
+		return "alphabetical order"
 	default:
 		return "unknown violation"
 	}
 }
+
+// Name returns a short, stable identifier of the violation type for use in
+// machine-readable output.
+func (v ViolationType) Name() string {
+	switch v {
+	case ViolationConstructor:
+		return "constructor"
+	case ViolationExported:
+		return "exported"
+	case ViolationMisplaced:
+		return "misplaced"
+	case ViolationAlphabetical:
+		return "alphabetical"
+	default:
+		return "unknown"
+	}
+}
@@ -1,6 +1,10 @@
 package config
 
-import "testing"
+import (
+	"flag"
+	"strings"
+	"testing"
+)
 
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
@@ -26,6 +30,27 @@ func TestDefaultConfig(t *testing.T) {
 	if !cfg.CheckExported {
 		t.Error("expected CheckExported=true")
 	}
+	if cfg.Package {
+		t.Error("expected Package=false")
+	}
+	if cfg.MoveMethods {
+		t.Error("expected MoveMethods=false")
+	}
+	if cfg.StandaloneConstructors {
+		t.Error("expected StandaloneConstructors=false")
+	}
+	if cfg.TypeCheck {
+		t.Error("expected TypeCheck=false")
+	}
+	if cfg.VerifyTypes {
+		t.Error("expected VerifyTypes=false")
+	}
+	if got := strings.Join(cfg.ConstructorPrefixes, ","); got != "New,Must,Or" {
+		t.Errorf("expected ConstructorPrefixes=New,Must,Or, got %s", got)
+	}
+	if len(cfg.ConstructorPatterns) != 0 {
+		t.Errorf("expected no ConstructorPatterns, got %v", cfg.ConstructorPatterns)
+	}
 }
 
 func TestViolationType_String(t *testing.T) {
@@ -36,6 +61,7 @@ func TestViolationType_String(t *testing.T) {
 	}{
 		{"constructor", ViolationConstructor, "constructor ordering"},
 		{"exported", ViolationExported, "exported before unexported"},
+		{"misplaced", ViolationMisplaced, "method outside struct file"},
 		{"unknown", ViolationType(99), "unknown violation"},
 	}
 
@@ -48,3 +74,56 @@ func TestViolationType_String(t *testing.T) {
 		})
 	}
 }
+
+func TestViolationType_Name(t *testing.T) {
+	tests := []struct {
+		v    ViolationType
+		want string
+	}{
+		{ViolationConstructor, "constructor"},
+		{ViolationExported, "exported"},
+		{ViolationMisplaced, "misplaced"},
+		{ViolationType(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.v.Name(); got != tt.want {
+			t.Errorf("ViolationType(%d).Name() = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("default config: unexpected error: %v", err)
+	}
+
+	cfg.ConstructorPatterns = []string{`^With[A-Z]`, `New(`}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for an invalid constructor pattern")
+	}
+}
+
+func TestListValue(t *testing.T) {
+	prefixes := []string{"New", "Must", "Or"}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(NewListValue(&prefixes, ","), "prefix", "")
+
+	if err := fs.Parse([]string{"-prefix", "New, Open", "-prefix=Make"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(prefixes, " "); got != "New Open Make" {
+		t.Errorf("prefixes = %q, want %q", got, "New Open Make")
+	}
+
+	patterns := []string{"a"}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(NewListValue(&patterns, ""), "pattern", "")
+	if err := fs.Parse([]string{"-pattern", "^New.{1,3}$"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 1 || patterns[0] != "^New.{1,3}$" {
+		t.Errorf("patterns = %q, want [^New.{1,3}$]", patterns)
+	}
+}
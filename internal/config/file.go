@@ -0,0 +1,330 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project configuration file.
+const FileName = ".funcorder-fix.yaml"
+
+// File is the content of a configuration file. Settings that are not present
+// in the file are nil and leave the inherited value unchanged.
+type File struct {
+	// Root stops the search for configuration files in parent directories.
+	Root bool `yaml:"root,omitempty"`
+
+	// Golangci reads the funcorder settings of the nearest golangci-lint
+	// configuration, which the files then override. Defaults to true.
+	Golangci *bool `yaml:"golangci,omitempty"`
+
+	// Rules toggles the ordering rules.
+	Rules *RulesFile `yaml:"rules,omitempty"`
+
+	// Constructors configures how constructors are recognized.
+	Constructors *ConstructorsFile `yaml:"constructors,omitempty"`
+
+	// Package analyzes all files of a package together.
+	Package *bool `yaml:"package,omitempty"`
+
+	// MoveMethods moves methods into the file declaring their struct.
+	MoveMethods *bool `yaml:"move-methods,omitempty"`
+
+	// VerifyTypes type-checks each fixed package before and after the fix.
+	VerifyTypes *bool `yaml:"verify-types,omitempty"`
+
+	// IncludeGenerated processes generated files when walking directories.
+	IncludeGenerated *bool `yaml:"include-generated,omitempty"`
+
+	// Exclude lists glob patterns of files and directories to skip. Patterns
+	// containing a slash are relative to the directory of the file; other
+	// patterns match the name of any file or directory below it.
+	Exclude []string `yaml:"exclude,omitempty"`
+
+	// Include lists glob patterns, resolved like Exclude, that restrict the
+	// processed files to those matching one of them.
+	Include []string `yaml:"include,omitempty"`
+
+	// GitIgnore skips files ignored by .gitignore files.
+	GitIgnore *bool `yaml:"gitignore,omitempty"`
+
+	// Tags lists the build tags used to resolve package patterns.
+	Tags []string `yaml:"tags,omitempty"`
+
+	// Jobs is the number of files or packages processed concurrently.
+	Jobs *int `yaml:"jobs,omitempty"`
+
+	// Format is the output format.
+	Format *string `yaml:"format,omitempty"`
+}
+
+// RulesFile toggles the ordering rules.
+type RulesFile struct {
+	Constructor               *bool `yaml:"constructor,omitempty"`
+	Exported                  *bool `yaml:"exported,omitempty"`
+	Alphabetical              *bool `yaml:"alphabetical,omitempty"`
+	StandaloneConstructors    *bool `yaml:"standalone-constructors,omitempty"`
+	ConstructorsBeforeMethods *bool `yaml:"constructors-before-methods,omitempty"`
+}
+
+// ConstructorsFile configures how constructors are recognized.
+type ConstructorsFile struct {
+	Prefixes []string `yaml:"prefixes,omitempty"`
+	Patterns []string `yaml:"patterns,omitempty"`
+	Types    *bool    `yaml:"types,omitempty"`
+}
+
+// ReadFile reads and decodes the configuration file at path. Unknown keys
+// are reported as errors.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Apply overrides the settings of cfg with the settings present in f. dir is
+// the directory of the file, used to resolve exclude patterns.
+func (f *File) Apply(cfg *Config, dir string) {
+	if r := f.Rules; r != nil {
+		setBool(&cfg.CheckConstructor, r.Constructor)
+		setBool(&cfg.CheckExported, r.Exported)
+		setBool(&cfg.Alphabetical, r.Alphabetical)
+		setBool(&cfg.StandaloneConstructors, r.StandaloneConstructors)
+		setBool(&cfg.ConstructorsBeforeMethods, r.ConstructorsBeforeMethods)
+	}
+	if c := f.Constructors; c != nil {
+		if c.Prefixes != nil {
+			cfg.ConstructorPrefixes = append([]string(nil), c.Prefixes...)
+		}
+		if c.Patterns != nil {
+			cfg.ConstructorPatterns = append([]string(nil), c.Patterns...)
+		}
+		setBool(&cfg.TypeCheck, c.Types)
+	}
+	setBool(&cfg.Package, f.Package)
+	setBool(&cfg.MoveMethods, f.MoveMethods)
+	if cfg.MoveMethods {
+		cfg.Package = true
+	}
+	setBool(&cfg.VerifyTypes, f.VerifyTypes)
+	setBool(&cfg.IncludeGenerated, f.IncludeGenerated)
+	for _, pattern := range f.Exclude {
+		cfg.Exclude = append(cfg.Exclude, ResolvePattern(pattern, dir))
+	}
+	if f.Include != nil {
+		cfg.Include = make([]string, len(f.Include))
+		for i, pattern := range f.Include {
+			cfg.Include[i] = ResolvePattern(pattern, dir)
+		}
+	}
+	setBool(&cfg.GitIgnore, f.GitIgnore)
+	if f.Tags != nil {
+		cfg.BuildTags = append([]string(nil), f.Tags...)
+	}
+	if f.Jobs != nil {
+		cfg.Jobs = *f.Jobs
+	}
+	if f.Format != nil {
+		cfg.Format = *f.Format
+	}
+}
+
+// setBool sets *dst to *src if src is not nil.
+func setBool(dst *bool, src *bool) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// FileOf returns a File holding every setting of cfg that a configuration
+// file can hold.
+func FileOf(cfg *Config) *File {
+	file := &File{
+		Rules: &RulesFile{
+			Constructor:               &cfg.CheckConstructor,
+			Exported:                  &cfg.CheckExported,
+			Alphabetical:              &cfg.Alphabetical,
+			StandaloneConstructors:    &cfg.StandaloneConstructors,
+			ConstructorsBeforeMethods: &cfg.ConstructorsBeforeMethods,
+		},
+		Constructors: &ConstructorsFile{
+			Prefixes: cfg.ConstructorPrefixes,
+			Patterns: cfg.ConstructorPatterns,
+			Types:    &cfg.TypeCheck,
+		},
+		Package:          &cfg.Package,
+		MoveMethods:      &cfg.MoveMethods,
+		VerifyTypes:      &cfg.VerifyTypes,
+		IncludeGenerated: &cfg.IncludeGenerated,
+		Exclude:          cfg.Exclude,
+		Include:          cfg.Include,
+		GitIgnore:        &cfg.GitIgnore,
+		Tags:             cfg.BuildTags,
+		Jobs:             &cfg.Jobs,
+		Format:           &cfg.Format,
+	}
+	return file
+}
+
+// Resolved is the effective configuration of a directory.
+type Resolved struct {
+	// Config is the merged configuration.
+	Config *Config
+
+	// Files lists the configuration files that were merged, in the order
+	// they were applied: the golangci-lint configuration, if any, first.
+	Files []string
+}
+
+// Loader resolves the effective configuration of a directory by merging the
+// configuration files found in it and its parents over a base Config, with
+// files in nested directories overriding those of their parents. The
+// funcorder settings of the nearest golangci-lint configuration are applied
+// before the files. Results are cached, and directories governed by the same
+// files share one Config.
+type Loader struct {
+	base     *Config
+	override func(*Config)
+
+	mu      sync.Mutex
+	byDir   map[string]*Resolved
+	byFiles map[string]*Config
+}
+
+// NewLoader returns a Loader that merges configuration files over base.
+// override, if not nil, is applied last to every merged Config, e.g. to let
+// explicitly set command-line flags win over the files.
+func NewLoader(base *Config, override func(*Config)) *Loader {
+	return &Loader{
+		base:     base,
+		override: override,
+		byDir:    make(map[string]*Resolved),
+		byFiles:  make(map[string]*Config),
+	}
+}
+
+// Load returns the effective configuration of dir.
+func (l *Loader) Load(dir string) (*Resolved, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r, ok := l.byDir[abs]; ok {
+		return r, nil
+	}
+
+	paths, files, err := findFiles(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings *FuncorderSettings
+	if useGolangci(files) {
+		path, err := findGolangci(abs)
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			if settings, err = ReadGolangci(path); err != nil {
+				return nil, err
+			}
+		}
+		if settings != nil {
+			paths = append([]string{path}, paths...)
+		}
+	}
+
+	key := strings.Join(paths, "\x00")
+	cfg, ok := l.byFiles[key]
+	if !ok {
+		merged := *l.base
+		cfg = &merged
+		cfg.ConstructorPrefixes = append([]string(nil), l.base.ConstructorPrefixes...)
+		cfg.ConstructorPatterns = append([]string(nil), l.base.ConstructorPatterns...)
+		cfg.Exclude = append([]string(nil), l.base.Exclude...)
+		cfg.Include = append([]string(nil), l.base.Include...)
+		if settings != nil {
+			settings.Apply(cfg, filepath.Dir(paths[0]))
+		}
+		for i, file := range files {
+			file.Apply(cfg, filepath.Dir(paths[len(paths)-len(files)+i]))
+		}
+		if l.override != nil {
+			l.override(cfg)
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		l.byFiles[key] = cfg
+	}
+
+	r := &Resolved{Config: cfg, Files: paths}
+	l.byDir[abs] = r
+	return r, nil
+}
+
+// useGolangci reports whether the golangci-lint settings apply under files,
+// given outermost first.
+func useGolangci(files []*File) bool {
+	use := true
+	for _, file := range files {
+		setBool(&use, file.Golangci)
+	}
+	return use
+}
+
+// findFiles reads the configuration files of dir and its parents, up to the
+// first file marked as root, and returns them outermost first.
+func findFiles(dir string) ([]string, []*File, error) {
+	var paths []string
+	var files []*File
+	for {
+		path := filepath.Join(dir, FileName)
+		file, err := ReadFile(path)
+		switch {
+		case err == nil:
+			paths = append(paths, path)
+			files = append(files, file)
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, nil, err
+		}
+		if file != nil && file.Root {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	// Reverse so that outer files are applied first.
+	for i, j := 0, len(paths)-1; i < j; i, j = i+1, j-1 {
+		paths[i], paths[j] = paths[j], paths[i]
+		files[i], files[j] = files[j], files[i]
+	}
+	return paths, files, nil
+}
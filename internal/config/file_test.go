@@ -0,0 +1,206 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"full.yaml": `root: true
+rules:
+  constructor: false
+  standalone-constructors: true
+constructors:
+  prefixes: [Make]
+  patterns: ["^Build[A-Z]"]
+  types: true
+move-methods: true
+verify-types: true
+exclude: ["*_gen.go"]
+format: json
+`,
+		"empty.yaml":   "",
+		"unknown.yaml": "rules:\n  sorted: true\n",
+	})
+
+	file, err := ReadFile(filepath.Join(dir, "full.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	file.Apply(cfg, dir)
+	if !file.Root || cfg.CheckConstructor || !cfg.CheckExported || !cfg.StandaloneConstructors ||
+		!cfg.TypeCheck || !cfg.MoveMethods || !cfg.Package || !cfg.VerifyTypes || cfg.Format != "json" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if strings.Join(cfg.ConstructorPrefixes, ",") != "Make" || strings.Join(cfg.ConstructorPatterns, ",") != "^Build[A-Z]" {
+		t.Errorf("unexpected constructor settings: %v, %v", cfg.ConstructorPrefixes, cfg.ConstructorPatterns)
+	}
+
+	if _, err := ReadFile(filepath.Join(dir, "empty.yaml")); err != nil {
+		t.Errorf("empty file: unexpected error: %v", err)
+	}
+	if _, err := ReadFile(filepath.Join(dir, "unknown.yaml")); err == nil || !strings.Contains(err.Error(), "unknown.yaml") {
+		t.Errorf("expected an error naming the file for unknown keys, got %v", err)
+	}
+}
+
+func TestLoader(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		FileName:                      "rules:\n  exported: false\nconstructors:\n  prefixes: [Make]\n",
+		"a/" + FileName:               "rules:\n  exported: true\nformat: json\n",
+		"a/b/c/keep.go":               "",
+		"root/" + FileName:            "root: true\n",
+		"bad/" + FileName:             "constructors:\n  patterns: ['(']\n",
+		"override/" + FileName:        "rules:\n  constructor: false\n",
+		"override/deeper/" + FileName: "",
+	})
+
+	loader := NewLoader(DefaultConfig(), nil)
+
+	top, err := loader.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top.Config.CheckExported || top.Config.ConstructorPrefixes[0] != "Make" || len(top.Files) == 0 {
+		t.Errorf("top: unexpected config %+v from %v", top.Config, top.Files)
+	}
+
+	// Nested files override their parents; unset settings are inherited.
+	nested, err := loader.Load(filepath.Join(dir, "a", "b", "c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nested.Config.CheckExported || nested.Config.Format != "json" || nested.Config.ConstructorPrefixes[0] != "Make" {
+		t.Errorf("nested: unexpected config %+v", nested.Config)
+	}
+	if n := len(nested.Files); n < 2 || nested.Files[n-1] != filepath.Join(dir, "a", FileName) {
+		t.Errorf("nested: expected the innermost file last, got %v", nested.Files)
+	}
+
+	// Directories governed by the same files share a Config.
+	sibling, err := loader.Load(filepath.Join(dir, "a", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sibling.Config != nested.Config {
+		t.Error("expected directories with the same files to share a Config")
+	}
+
+	// root stops the search.
+	root, err := loader.Load(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(root.Files) != 1 || !root.Config.CheckExported || root.Config.ConstructorPrefixes[0] != "New" {
+		t.Errorf("root: expected only its own file, got %v and %+v", root.Files, root.Config)
+	}
+
+	if _, err := loader.Load(filepath.Join(dir, "bad")); err == nil || !strings.Contains(err.Error(), "invalid constructor pattern") {
+		t.Errorf("bad: expected a validation error, got %v", err)
+	}
+
+	// The override is applied after the files.
+	withFlags := NewLoader(DefaultConfig(), func(cfg *Config) { cfg.CheckConstructor = true })
+	over, err := withFlags.Load(filepath.Join(dir, "override", "deeper"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !over.Config.CheckConstructor {
+		t.Error("expected the override to win over the files")
+	}
+}
+
+func TestConfig_Excluded(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	(&File{Exclude: []string{"*_gen.go", "testdata", "internal/legacy/*"}}).Apply(cfg, dir)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(dir, "api_gen.go"), true},
+		{filepath.Join(dir, "pkg", "testdata", "x.go"), true},
+		{filepath.Join(dir, "internal", "legacy", "old.go"), true},
+		{filepath.Join(dir, "internal", "legacy", "sub", "old.go"), true},
+		{filepath.Join(dir, "internal", "current.go"), false},
+		{filepath.Join(dir, "api.go"), false},
+	}
+	for _, tt := range tests {
+		if got := cfg.Excluded(tt.path); got != tt.want {
+			t.Errorf("Excluded(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// Patterns without a slash only match below the directory of the file,
+	// not the names of that directory and its parents.
+	dir = filepath.Join(t.TempDir(), "module")
+	cfg = DefaultConfig()
+	(&File{Exclude: []string{"module", "*"}}).Apply(cfg, dir)
+	if cfg.Excluded(dir) || !cfg.Excluded(filepath.Join(dir, "api.go")) {
+		t.Errorf("expected * to match only below %s", dir)
+	}
+	cfg = DefaultConfig()
+	(&File{Exclude: []string{"module"}}).Apply(cfg, dir)
+	if cfg.Excluded(filepath.Join(dir, "api.go")) || !cfg.Excluded(filepath.Join(dir, "pkg", "module", "x.go")) {
+		t.Errorf("expected module to match only below %s", dir)
+	}
+
+	if err := (&Config{Exclude: []string{"["}}).Validate(); err == nil {
+		t.Error("expected an error for a malformed exclude pattern")
+	}
+}
+
+func TestConfig_Included(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	if !cfg.Included(filepath.Join(dir, "any.go")) {
+		t.Error("expected every file to be included without patterns")
+	}
+
+	(&File{Include: []string{"*_service.go", "api/*"}}).Apply(cfg, dir)
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(dir, "user_service.go"), true},
+		{filepath.Join(dir, "api", "handler.go"), true},
+		{filepath.Join(dir, "api", "v1", "handler.go"), true},
+		{filepath.Join(dir, "user.go"), false},
+	}
+	for _, tt := range tests {
+		if got := cfg.Included(tt.path); got != tt.want {
+			t.Errorf("Included(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// A nested file replaces the inherited include patterns.
+	(&File{Include: []string{"*.go"}}).Apply(cfg, dir)
+	if !cfg.Included(filepath.Join(dir, "user.go")) || len(cfg.Include) != 1 {
+		t.Errorf("expected the include patterns to be replaced, got %v", cfg.Include)
+	}
+
+	if err := (&Config{Include: []string{"["}}).Validate(); err == nil {
+		t.Error("expected an error for a malformed include pattern")
+	}
+}
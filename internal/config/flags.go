@@ -0,0 +1,53 @@
+package config
+
+import (
+	"flag"
+	"strings"
+)
+
+// listValue is a repeatable flag.Value holding a list of strings. The first
+// Set replaces the default values instead of appending to them.
+type listValue struct {
+	values *[]string
+	sep    string
+	set    bool
+}
+
+// NewListValue returns a flag.Value that stores its values in *p. Every
+// occurrence of the flag adds to the list; when sep is not empty a single
+// occurrence may also hold several values separated by sep. An empty value
+// clears the list.
+func NewListValue(p *[]string, sep string) flag.Value {
+	return &listValue{values: p, sep: sep}
+}
+
+// String returns the values joined by the separator.
+func (l *listValue) String() string {
+	if l.values == nil {
+		return ""
+	}
+	sep := l.sep
+	if sep == "" {
+		sep = " "
+	}
+	return strings.Join(*l.values, sep)
+}
+
+// Set adds the values in s.
+func (l *listValue) Set(s string) error {
+	if !l.set {
+		*l.values = nil
+		l.set = true
+	}
+
+	values := []string{s}
+	if l.sep != "" {
+		values = strings.Split(s, l.sep)
+	}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			*l.values = append(*l.values, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// golangciFileNames lists the golangci-lint configuration files funcorder
+// settings are read from, in the order golangci-lint looks for them.
+var golangciFileNames = []string{".golangci.yml", ".golangci.yaml", ".golangci.json"}
+
+// FuncorderSettings are the settings of the funcorder linter in a
+// golangci-lint configuration. Missing settings take the linter's defaults.
+type FuncorderSettings struct {
+	// Constructor checks that constructors follow their struct declaration
+	// and precede its methods. Defaults to true.
+	Constructor *bool `yaml:"constructor"`
+
+	// StructMethod checks that exported methods precede unexported ones.
+	// Defaults to true.
+	StructMethod *bool `yaml:"struct-method"`
+
+	// Alphabetical checks that methods are sorted by name within their
+	// group. Defaults to false.
+	Alphabetical *bool `yaml:"alphabetical"`
+
+	// ExcludePaths lists the linters.exclusions.paths of the configuration,
+	// regular expressions of the files no linter reports on.
+	ExcludePaths []string `yaml:"-"`
+}
+
+// golangciFile is the part of a golangci-lint configuration that concerns
+// funcorder. Both the v1 (linters-settings) and the v2 (linters.settings)
+// layouts are understood.
+type golangciFile struct {
+	Linters struct {
+		Default   string   `yaml:"default"`
+		EnableAll bool     `yaml:"enable-all"`
+		Enable    []string `yaml:"enable"`
+		Disable   []string `yaml:"disable"`
+		Settings  struct {
+			Funcorder *FuncorderSettings `yaml:"funcorder"`
+		} `yaml:"settings"`
+		Exclusions struct {
+			Paths []string `yaml:"paths"`
+		} `yaml:"exclusions"`
+	} `yaml:"linters"`
+
+	LintersSettings struct {
+		Funcorder *FuncorderSettings `yaml:"funcorder"`
+	} `yaml:"linters-settings"`
+}
+
+// ReadGolangci reads the golangci-lint configuration at path and returns the
+// funcorder settings it applies, or nil if it does not enable funcorder.
+func ReadGolangci(path string) (*FuncorderSettings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file golangciFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	linters := file.Linters
+	enabled := linters.Default == "all" || linters.EnableAll || slices.Contains(linters.Enable, "funcorder")
+	if !enabled || slices.Contains(linters.Disable, "funcorder") {
+		return nil, nil
+	}
+
+	settings := linters.Settings.Funcorder
+	if settings == nil {
+		settings = file.LintersSettings.Funcorder
+	}
+	if settings == nil {
+		settings = &FuncorderSettings{}
+	}
+	settings.ExcludePaths = linters.Exclusions.Paths
+	return settings, nil
+}
+
+// Apply configures cfg to enforce the same rules as the linter. funcorder
+// has no rule for methods named like constructors, so that check is
+// disabled, and it only recognizes constructors named New*. dir is the
+// directory of the golangci-lint configuration, which the exclusion paths
+// are relative to.
+func (s *FuncorderSettings) Apply(cfg *Config, dir string) {
+	cfg.CheckConstructor = false
+	cfg.StandaloneConstructors = s.Constructor == nil || *s.Constructor
+	cfg.ConstructorsBeforeMethods = true
+	cfg.ConstructorPrefixes = []string{"New"}
+	cfg.ConstructorPatterns = nil
+	cfg.CheckExported = s.StructMethod == nil || *s.StructMethod
+	cfg.Alphabetical = s.Alphabetical != nil && *s.Alphabetical
+	for _, path := range s.ExcludePaths {
+		if pattern, ok := exclusionPattern(path); ok {
+			cfg.Exclude = append(cfg.Exclude, ResolvePattern(pattern, dir))
+		}
+	}
+}
+
+// exclusionPattern translates an exclusion path of golangci-lint, a regular
+// expression, into a glob pattern. Only literal paths with escaped dots,
+// optional ^ and $ anchors and .* wildcards can be translated; false is
+// returned for any other expression. A pattern without a slash is matched
+// against any part of a file or directory name unless it is anchored.
+func exclusionPattern(re string) (string, bool) {
+	start := strings.HasPrefix(re, "^")
+	end := strings.HasSuffix(re, "$") && !strings.HasSuffix(re, `\$`)
+	re = strings.TrimPrefix(re, "^")
+	if end {
+		re = strings.TrimSuffix(re, "$")
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(re); i++ {
+		switch c := re[i]; {
+		case c == '\\' && i+1 < len(re) && strings.IndexByte(`./-_`, re[i+1]) >= 0:
+			i++
+			b.WriteByte(re[i])
+		case c == '.' && i+1 < len(re) && re[i+1] == '*':
+			i++
+			b.WriteByte('*')
+		case c == '.':
+			b.WriteByte('?')
+		case strings.IndexByte(`\^$*+?()[]{}|`, c) >= 0:
+			return "", false
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	pattern := b.String()
+	if pattern == "" {
+		return "", false
+	}
+	if !strings.Contains(pattern, "/") {
+		if !start && !strings.HasPrefix(pattern, "*") {
+			pattern = "*" + pattern
+		}
+		if !end && !strings.HasSuffix(pattern, "*") {
+			pattern += "*"
+		}
+	}
+	return pattern, true
+}
+
+// findGolangci returns the path of the golangci-lint configuration nearest
+// to dir, searching dir and its parents, or "" if there is none.
+func findGolangci(dir string) (string, error) {
+	for {
+		for _, name := range golangciFileNames {
+			path := filepath.Join(dir, name)
+			_, err := os.Stat(path)
+			if err == nil {
+				return path, nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadGolangci(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"v1.yml": `linters:
+  enable:
+    - funcorder
+linters-settings:
+  funcorder:
+    constructor: false
+    struct-method: true
+    alphabetical: true
+`,
+		"v2.yml": `version: "2"
+linters:
+  default: none
+  enable: [funcorder]
+  settings:
+    funcorder:
+      struct-method: false
+`,
+		"all.json":      `{"linters": {"default": "all"}}`,
+		"off.yml":       "linters:\n  enable: [govet]\n  settings:\n    funcorder:\n      constructor: false\n",
+		"disabled.yml":  "linters:\n  enable-all: true\n  disable: [funcorder]\n",
+		"malformed.yml": "linters: [\n",
+	})
+
+	tests := []struct {
+		file         string
+		enabled      bool
+		standalone   bool
+		checkExports bool
+		alphabetical bool
+	}{
+		{"v1.yml", true, false, true, true},
+		{"v2.yml", true, true, false, false},
+		{"all.json", true, true, true, false},
+		{"off.yml", false, false, false, false},
+		{"disabled.yml", false, false, false, false},
+	}
+	for _, tt := range tests {
+		settings, err := ReadGolangci(filepath.Join(dir, tt.file))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.file, err)
+			continue
+		}
+		if (settings != nil) != tt.enabled {
+			t.Errorf("%s: enabled = %v, want %v", tt.file, settings != nil, tt.enabled)
+			continue
+		}
+		if settings == nil {
+			continue
+		}
+		cfg := DefaultConfig()
+		settings.Apply(cfg, dir)
+		if cfg.CheckConstructor || cfg.StandaloneConstructors != tt.standalone || cfg.CheckExported != tt.checkExports ||
+			cfg.Alphabetical != tt.alphabetical {
+			t.Errorf("%s: unexpected config %+v", tt.file, cfg)
+		}
+		// funcorder only recognizes New* functions, anywhere between the
+		// struct and its methods.
+		if !cfg.ConstructorsBeforeMethods || strings.Join(cfg.ConstructorPrefixes, ",") != "New" {
+			t.Errorf("%s: unexpected constructor rule %+v", tt.file, cfg)
+		}
+	}
+
+	if _, err := ReadGolangci(filepath.Join(dir, "malformed.yml")); err == nil || !strings.Contains(err.Error(), "malformed.yml") {
+		t.Errorf("expected an error naming the file, got %v", err)
+	}
+}
+
+func TestLoader_Golangci(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".golangci.yml":         "linters:\n  enable: [funcorder]\n  settings:\n    funcorder:\n      struct-method: false\n  exclusions:\n    paths: [pkg/gen, '_mock\\.go$']\n",
+		"pkg/keep.go":           "",
+		"nested/.golangci.yaml": "linters:\n  enable: [funcorder]\n",
+		"override/" + FileName:  "rules:\n  exported: true\n",
+		"optout/" + FileName:    "golangci: false\n",
+	})
+	loader := NewLoader(DefaultConfig(), nil)
+
+	pkg, err := loader.Load(filepath.Join(dir, "pkg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Config.CheckExported || pkg.Config.CheckConstructor || !pkg.Config.StandaloneConstructors {
+		t.Errorf("pkg: expected the linter's rules, got %+v", pkg.Config)
+	}
+	if len(pkg.Files) != 1 || pkg.Files[0] != filepath.Join(dir, ".golangci.yml") {
+		t.Errorf("pkg: expected the golangci-lint file, got %v", pkg.Files)
+	}
+	// Exclusion paths are relative to the golangci-lint configuration.
+	for path, want := range map[string]bool{
+		filepath.Join(dir, "pkg", "gen", "api.go"): true,
+		filepath.Join(dir, "pkg", "user_mock.go"):  true,
+		filepath.Join(dir, "pkg", "keep.go"):       false,
+		filepath.Join(dir, "pkg", "pkg", "gen.go"): false,
+	} {
+		if got := pkg.Config.Excluded(path); got != want {
+			t.Errorf("pkg: Excluded(%s) = %v, want %v", path, got, want)
+		}
+	}
+
+	// The nearest golangci-lint configuration wins.
+	nested, err := loader.Load(filepath.Join(dir, "nested"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nested.Config.CheckExported {
+		t.Errorf("nested: expected the nested defaults, got %+v", nested.Config)
+	}
+
+	// funcorder-fix files override the linter settings.
+	override, err := loader.Load(filepath.Join(dir, "override"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !override.Config.CheckExported || !override.Config.StandaloneConstructors || len(override.Files) != 2 {
+		t.Errorf("override: unexpected config %+v from %v", override.Config, override.Files)
+	}
+
+	optout, err := loader.Load(filepath.Join(dir, "optout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !optout.Config.CheckExported || !optout.Config.CheckConstructor || len(optout.Files) != 1 {
+		t.Errorf("optout: expected the defaults, got %+v from %v", optout.Config, optout.Files)
+	}
+}
+
+func TestExclusionPattern(t *testing.T) {
+	tests := []struct {
+		re   string
+		want string
+		ok   bool
+	}{
+		{"examples/input", "examples/input", true},
+		{"^third_party/", "third_party/", true},
+		{`_test\.go$`, "*_test.go", true},
+		{"^mocks$", "mocks", true},
+		{"generated", "*generated*", true},
+		{`internal/.*\.pb\.go$`, "internal/*.pb.go", true},
+		{"a.b", "*a?b*", true},
+		{"(foo|bar)", "", false},
+		{`\d+`, "", false},
+		{"^$", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := exclusionPattern(tt.re)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("exclusionPattern(%q) = %q, %v, want %q, %v", tt.re, got, ok, tt.want, tt.ok)
+		}
+	}
+}
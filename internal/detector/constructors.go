@@ -0,0 +1,224 @@
+package detector
+
+import (
+	"fmt"
+	"go/ast"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+)
+
+// ConstructorGroup lists the standalone constructors that belong directly
+// after a single type declaration. For a grouped declaration (type ( ... ))
+// the constructors of all its structs are listed in spec order.
+type ConstructorGroup struct {
+	// Decl is the type declaration the constructors should follow.
+	Decl *ast.GenDecl
+
+	// Constructors are the constructors in their expected order.
+	Constructors []*MethodInfo
+
+	// InPlace reports whether the constructors already directly follow Decl
+	// in the expected order or, with ConstructorsBeforeMethods, whether each
+	// follows Decl and precedes the methods of its struct.
+	InPlace bool
+
+	// Moved are the constructors the fix moves directly after Decl, in the
+	// expected order: all of them unless InPlace or, with
+	// ConstructorsBeforeMethods, only those out of place.
+	Moved []*MethodInfo
+}
+
+// StandaloneConstructorGroups returns one ConstructorGroup per type
+// declaration in file that has standalone constructors, in source order.
+// structs must have been collected with standalone constructors enabled.
+func (d *Detector) StandaloneConstructorGroups(file *ast.File, structs map[string]*StructMethods) []*ConstructorGroup {
+	var groups []*ConstructorGroup
+
+	for i, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+
+		group := &ConstructorGroup{Decl: genDecl}
+		var owners []*StructMethods
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			if sm, exists := structs[typeSpec.Name.Name]; exists && sm.StructPos == typeSpec.Pos() {
+				group.Constructors = append(group.Constructors, sm.StandaloneConstructors...)
+				for range sm.StandaloneConstructors {
+					owners = append(owners, sm)
+				}
+			}
+		}
+		if len(group.Constructors) == 0 {
+			continue
+		}
+
+		if d.config.ConstructorsBeforeMethods {
+			for k, c := range group.Constructors {
+				if indexOfDecl(file, c.FuncDecl) < i || firstMethodBefore(file, owners[k], c) != nil {
+					group.Moved = append(group.Moved, c)
+				}
+			}
+			group.InPlace = len(group.Moved) == 0
+			groups = append(groups, group)
+			continue
+		}
+
+		group.InPlace = true
+		for k, c := range group.Constructors {
+			next := i + 1 + k
+			if next >= len(file.Decls) || file.Decls[next] != c.FuncDecl {
+				group.InPlace = false
+				group.Moved = group.Constructors
+				break
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// collectStandaloneConstructor records fn as a standalone constructor when it
+// constructs a struct declared in file. Without type information the name
+// must also match the constructor rule. Constructors suppressed by a
+// directive in dirs, or of an ignored struct, are left where they are.
+func (d *Detector) collectStandaloneConstructor(file *ast.File, dirs *directives, fn *ast.FuncDecl, structs map[string]*StructMethods) {
+	sm, exists := structs[d.standaloneConstructedType(fn)]
+	if !exists || sm.StructPos < file.FileStart || sm.StructPos > file.FileEnd {
+		return
+	}
+	if sm.Ignored || dirs.ignores(fn.Pos(), fn.Doc) {
+		return
+	}
+	info := newMethodInfo(fn)
+	info.IsConstructor = true
+	sm.StandaloneConstructors = append(sm.StandaloneConstructors, info)
+}
+
+// checkStandaloneConstructors reports standalone constructors that are not
+// placed directly after the declaration of the struct they return or, with
+// ConstructorsBeforeMethods, that precede it or follow one of its methods.
+func (d *Detector) checkStandaloneConstructors(file *ast.File, structs map[string]*StructMethods, report *Report) {
+	for _, group := range d.StandaloneConstructorGroups(file, structs) {
+		if group.InPlace {
+			continue
+		}
+		if d.config.ConstructorsBeforeMethods {
+			d.reportMovedConstructors(file, structs, group, report)
+			continue
+		}
+
+		// Constructors that already directly follow the declaration, up to
+		// the first one out of place, are not reported.
+		next := indexOfDecl(file, group.Decl) + 1
+		for _, c := range group.Constructors {
+			if next >= 0 && next < len(file.Decls) && file.Decls[next] == c.FuncDecl {
+				next++
+				continue
+			}
+			next = -1
+
+			typeName := d.standaloneConstructedType(c.FuncDecl)
+			report.AddViolation(newViolation(
+				config.ViolationConstructor,
+				d.fset,
+				c.FuncDecl,
+				typeName,
+				fmt.Sprintf("constructor %s should be placed right after type %s", c.Name, typeName),
+				SuggestedFix{
+					TargetPos:  group.Decl.End(),
+					TargetName: typeName,
+				},
+			))
+		}
+	}
+}
+
+// reportMovedConstructors reports the constructors of group that precede the
+// declaration of their struct or follow one of its methods.
+func (d *Detector) reportMovedConstructors(file *ast.File, structs map[string]*StructMethods, group *ConstructorGroup, report *Report) {
+	for _, c := range group.Moved {
+		typeName := d.standaloneConstructedType(c.FuncDecl)
+		message := fmt.Sprintf("constructor %s should be placed after type %s", c.Name, typeName)
+		if m := firstMethodBefore(file, structs[typeName], c); m != nil {
+			message = fmt.Sprintf("constructor %s should be placed before method %s of type %s", c.Name, m.Name, typeName)
+		}
+		report.AddViolation(newViolation(
+			config.ViolationConstructor,
+			d.fset,
+			c.FuncDecl,
+			typeName,
+			message,
+			SuggestedFix{
+				TargetPos:  group.Decl.End(),
+				TargetName: typeName,
+			},
+		))
+	}
+}
+
+// firstMethodBefore returns the first method of sm declared in file before
+// the constructor c, or nil if there is none.
+func firstMethodBefore(file *ast.File, sm *StructMethods, c *MethodInfo) *MethodInfo {
+	for _, m := range sm.Methods {
+		if m.Pos >= file.FileStart && m.Pos <= file.FileEnd && m.Pos < c.Pos {
+			return m
+		}
+	}
+	return nil
+}
+
+// ConstructedTypeName returns the name of the type a receiver-less function
+// constructs: its only result is T or *T, or its results are (T, error) or
+// (*T, error). It returns "" for any other function.
+func ConstructedTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv != nil || fn.Type.Results == nil {
+		return ""
+	}
+
+	var results []ast.Expr
+	for _, field := range fn.Type.Results.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			results = append(results, field.Type)
+		}
+	}
+
+	switch len(results) {
+	case 1:
+	case 2:
+		if ident, ok := results[1].(*ast.Ident); !ok || ident.Name != "error" {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	if star, ok := results[0].(*ast.StarExpr); ok {
+		results[0] = star.X
+	}
+	switch t := results[0].(type) {
+	case *ast.Ident, *ast.IndexExpr, *ast.IndexListExpr:
+		return GetReceiverTypeName(t)
+	}
+	return ""
+}
+
+// indexOfDecl returns the index of decl in file.Decls, or -1.
+func indexOfDecl(file *ast.File, decl ast.Decl) int {
+	for i, d := range file.Decls {
+		if d == decl {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,121 @@
+package detector_test
+
+import (
+	"go/ast"
+	"strings"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/detector"
+)
+
+func TestConstructedTypeName(t *testing.T) {
+	tests := []struct {
+		decl string
+		want string
+	}{
+		{"func NewS() *S { return nil }", "S"},
+		{"func NewS() S { return S{} }", "S"},
+		{"func NewS() (*S, error) { return nil, nil }", "S"},
+		{"func NewS() (s *S, err error) { return }", "S"},
+		{"func NewBox[T any]() *Box[T] { return nil }", "Box"},
+		{"func NewS() (*S, bool) { return nil, false }", ""},
+		{"func NewS() []*S { return nil }", ""},
+		{"func NewS() **S { return nil }", ""},
+		{"func NewS() {}", ""},
+		{"func (o *O) NewS() *S { return nil }", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.decl, func(t *testing.T) {
+			file, _ := parseSource(t, "package p\n"+tt.decl)
+			fn := file.Decls[0].(*ast.FuncDecl)
+			if got := detector.ConstructedTypeName(fn); got != tt.want {
+				t.Errorf("ConstructedTypeName(%q) = %q, want %q", tt.decl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect_StandaloneConstructors(t *testing.T) {
+	const src = `package p
+type (
+	A struct{}
+	B struct{}
+)
+func NewA() *A { return &A{} }
+func helper() {}
+func NewB() *B { return &B{} }
+func buildB() *B { return &B{} }`
+
+	file, fset := parseSource(t, src)
+	cfg := config.DefaultConfig()
+	cfg.StandaloneConstructors = true
+	d := detector.NewDetector(fset, cfg)
+	report := d.Detect(file, "test.go")
+
+	// NewA directly follows the grouped declaration; NewB does not.
+	// buildB does not match the constructor naming rule.
+	if len(report.Violations) != 1 {
+		t.Fatalf("expected 1 violation, got %d: %v", len(report.Violations), report.Violations)
+	}
+	v := report.Violations[0]
+	if v.MethodName != "NewB" || v.StructName != "B" || v.Type != config.ViolationConstructor {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+}
+
+func TestDetect_StandaloneConstructorsInPlace(t *testing.T) {
+	const src = `package p
+type S struct{}
+func NewS() *S { return &S{} }
+func MustS() S { return S{} }
+func (s *S) Run() {}`
+
+	file, fset := parseSource(t, src)
+	cfg := config.DefaultConfig()
+	cfg.StandaloneConstructors = true
+	d := detector.NewDetector(fset, cfg)
+	report := d.Detect(file, "test.go")
+
+	if report.HasViolations() {
+		t.Errorf("expected 0 violations, got %d: %v", len(report.Violations), report.Violations)
+	}
+}
+
+func TestDetect_GolangciConstructors(t *testing.T) {
+	// The cases upstream funcorder accepts and rejects: a constructor named
+	// New* must follow its struct and precede the methods of the struct.
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{"directly after", "type S struct{}\nfunc NewS() *S { return nil }\nfunc (s *S) Run() {}", nil},
+		{"after a const", "type S struct{}\nconst c = 1\nfunc NewS() *S { return nil }\nfunc (s *S) Run() {}", nil},
+		{"after a function", "type S struct{}\nfunc helper() {}\nfunc NewS() (*S, error) { return nil, nil }\nfunc (s *S) Run() {}", nil},
+		{"without methods", "type S struct{}\nvar v int\nfunc NewS() S { return S{} }", nil},
+		{"not named New", "func MustS() *S { return nil }\ntype S struct{}\nfunc (s *S) Run() {}\nfunc OrS() *S { return nil }", nil},
+		{"before the struct", "func NewS() *S { return nil }\ntype S struct{}\nfunc (s *S) Run() {}",
+			[]string{"constructor NewS should be placed after type S"}},
+		{"after a method", "type S struct{}\nfunc (s *S) Run() {}\nfunc NewS() *S { return nil }",
+			[]string{"constructor NewS should be placed before method Run of type S"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, fset := parseSource(t, "package p\n"+tt.src)
+			cfg := config.DefaultConfig()
+			(&config.FuncorderSettings{}).Apply(cfg, t.TempDir())
+			report := detector.NewDetector(fset, cfg).Detect(file, "test.go")
+
+			var got []string
+			for _, v := range report.Violations {
+				got = append(got, v.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
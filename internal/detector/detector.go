@@ -13,13 +13,20 @@ import (
 type Detector struct {
 	fset   *token.FileSet
 	config *config.Config
+
+	// matcher classifies constructors by name.
+	matcher *constructorMatcher
+
+	// constructors holds type-checked constructor classifications, if any.
+	constructors TypedConstructors
 }
 
 // NewDetector creates a new Detector with the given file set and configuration.
 func NewDetector(fset *token.FileSet, cfg *config.Config) *Detector {
 	return &Detector{
-		fset:   fset,
-		config: cfg,
+		fset:    fset,
+		config:  cfg,
+		matcher: newConstructorMatcher(cfg),
 	}
 }
 
@@ -38,10 +45,13 @@ func (d *Detector) Detect(file *ast.File, filePath string) *Report {
 		d.checkStructMethods(sm, report)
 	}
 
+	// Check placement of receiver-less constructors
+	if d.config.StandaloneConstructors {
+		d.checkStandaloneConstructors(file, structs, report)
+	}
+
 	// Sort violations by position
-	sort.Slice(report.Violations, func(i, j int) bool {
-		return report.Violations[i].MethodPos < report.Violations[j].MethodPos
-	})
+	report.sort()
 
 	return report
 }
@@ -62,9 +72,43 @@ func (d *Detector) GetMethodsToReorder(sm *StructMethods) []*MethodInfo {
 
 // collectStructMethods collects all methods grouped by their receiver type.
 func (d *Detector) collectStructMethods(file *ast.File) map[string]*StructMethods {
+	return d.collectStructMethodsFrom([]*ast.File{file})
+}
+
+// collectStructMethodsFrom collects the methods of every struct declared in
+// files, grouping methods by receiver type across all of the files.
+func (d *Detector) collectStructMethodsFrom(files []*ast.File) map[string]*StructMethods {
 	structs := make(map[string]*StructMethods)
 
+	dirs := make([]*directives, len(files))
+	for i, file := range files {
+		dirs[i] = newDirectives(d.fset, file, d.config.NoIgnore)
+	}
+
 	// First, collect all struct type declarations
+	for i, file := range files {
+		d.collectStructTypes(file, dirs[i], structs)
+	}
+
+	// Then, collect all method declarations and group them by receiver
+	for i, file := range files {
+		d.collectMethods(file, dirs[i], structs)
+	}
+
+	// Sort methods by position for each struct and categorize them
+	for _, sm := range structs {
+		sort.Slice(sm.Methods, func(i, j int) bool {
+			return sm.Methods[i].Pos < sm.Methods[j].Pos
+		})
+		sm.CategorizeMethods()
+	}
+
+	return structs
+}
+
+// collectStructTypes adds an empty StructMethods for every struct type declared in file.
+// Structs suppressed by a directive in dirs are marked as ignored.
+func (d *Detector) collectStructTypes(file *ast.File, dirs *directives, structs map[string]*StructMethods) {
 	for _, decl := range file.Decls {
 		if genDecl, ok := decl.(*ast.GenDecl); ok {
 			for _, spec := range genDecl.Specs {
@@ -75,42 +119,53 @@ func (d *Detector) collectStructMethods(file *ast.File) map[string]*StructMethod
 							StructPos:  typeSpec.Pos(),
 							StructEnd:  typeSpec.End(),
 							Methods:    []*MethodInfo{},
+
+							Alphabetical:    d.config.Alphabetical,
+							MixedVisibility: !d.config.CheckExported,
+							Ignored:         dirs.ignores(typeSpec.Pos(), typeSpec.Doc, genDecl.Doc),
 						}
 					}
 				}
 			}
 		}
 	}
+}
 
-	// Then, collect all method declarations and group them by receiver
+// collectMethods appends every method declared in file to the StructMethods of its receiver.
+// Standalone constructors are collected as well when they are enabled. Methods
+// suppressed by a directive in dirs, or belonging to an ignored struct, are
+// marked as ignored.
+func (d *Detector) collectMethods(file *ast.File, dirs *directives, structs map[string]*StructMethods) {
 	for _, decl := range file.Decls {
 		if fn, ok := decl.(*ast.FuncDecl); ok {
 			if fn.Recv != nil && len(fn.Recv.List) > 0 {
 				receiverType := GetReceiverTypeName(fn.Recv.List[0].Type)
 				if sm, exists := structs[receiverType]; exists {
 					methodInfo := newMethodInfo(fn)
+					// Without the constructor rule, methods named like
+					// constructors are ordered like any other.
+					methodInfo.IsConstructor = d.config.CheckConstructor && d.isConstructorOf(fn, receiverType)
+					methodInfo.Ignored = sm.Ignored || dirs.ignores(fn.Pos(), fn.Doc)
 					sm.Methods = append(sm.Methods, methodInfo)
 				}
+			} else if d.config.StandaloneConstructors {
+				d.collectStandaloneConstructor(file, dirs, fn, structs)
 			}
 		}
 	}
-
-	// Sort methods by position for each struct and categorize them
-	for _, sm := range structs {
-		sort.Slice(sm.Methods, func(i, j int) bool {
-			return sm.Methods[i].Pos < sm.Methods[j].Pos
-		})
-		sm.CategorizeMethods()
-	}
-
-	return structs
 }
 
 // checkStructMethods checks a struct's methods for ordering violations.
 func (d *Detector) checkStructMethods(sm *StructMethods, report *Report) {
-	if len(sm.Methods) <= 1 {
+	if len(sm.Methods) == 0 {
 		return
 	}
+	order := newStructOrder(d.fset, sm)
+	report.Structs = append(report.Structs, order)
+	if len(sm.Methods) == 1 {
+		return
+	}
+	violations := len(report.Violations)
 
 	// Check constructor ordering (constructors should come after struct)
 	if d.config.CheckConstructor {
@@ -121,6 +176,15 @@ func (d *Detector) checkStructMethods(sm *StructMethods, report *Report) {
 	if d.config.CheckExported {
 		d.checkExportedOrdering(sm, report)
 	}
+
+	// Check alphabetical ordering within each group
+	if d.config.Alphabetical {
+		d.checkAlphabeticalOrdering(sm, report)
+	}
+
+	if len(report.Violations) > violations && sm.NeedsReordering() {
+		report.Orders = append(report.Orders, order)
+	}
 }
 
 // checkConstructorOrdering checks that constructors appear after struct definition
@@ -178,3 +242,32 @@ func (d *Detector) checkExportedOrdering(sm *StructMethods, report *Report) {
 		}
 	}
 }
+
+// checkAlphabeticalOrdering checks that the methods of each group
+// (constructors, exported, unexported) appear sorted by name.
+func (d *Detector) checkAlphabeticalOrdering(sm *StructMethods, report *Report) {
+	kinds := []string{"constructor", "exported method", "unexported method"}
+	for i, group := range sm.groups() {
+		for j, method := range group {
+			// Report the method once, relative to the first earlier method
+			// of its group that it should precede.
+			for _, earlier := range group[:j] {
+				if earlier.Name > method.Name {
+					report.AddViolation(newViolation(
+						config.ViolationAlphabetical,
+						d.fset,
+						method.FuncDecl,
+						sm.StructName,
+						fmt.Sprintf("%s %s should appear before %s %s in alphabetical order",
+							kinds[i], method.Name, kinds[i], earlier.Name),
+						SuggestedFix{
+							TargetPos:  earlier.Pos,
+							TargetName: earlier.Name,
+						},
+					))
+					break
+				}
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"strings"
 	"testing"
 
 	"github.com/vajrock/funcorder-fix/internal/config"
@@ -162,6 +163,165 @@ func (s *S) Run()     {}`
 	}
 }
 
+func TestDetect_Alphabetical(t *testing.T) {
+	const src = `package p
+type S struct{}
+func (s *S) NewS() *S  { return s }
+func (s *S) MustS() *S { return s }
+func (s *S) Stop()     {}
+func (s *S) Run()      {}
+func (s *S) Close()    {}
+func (s *S) b()        {}
+func (s *S) a()        {}`
+
+	file, fset := parseSource(t, src)
+	cfg := config.DefaultConfig()
+
+	// Source order within the groups is fine by default.
+	if report := detector.NewDetector(fset, cfg).Detect(file, "test.go"); report.HasViolations() {
+		t.Fatalf("expected 0 violations without Alphabetical, got %v", report.Violations)
+	}
+
+	cfg.Alphabetical = true
+	report := detector.NewDetector(fset, cfg).Detect(file, "test.go")
+
+	want := []string{
+		"constructor MustS should appear before constructor NewS in alphabetical order",
+		"exported method Run should appear before exported method Stop in alphabetical order",
+		"exported method Close should appear before exported method Stop in alphabetical order",
+		"unexported method a should appear before unexported method b in alphabetical order",
+	}
+	if len(report.Violations) != len(want) {
+		t.Fatalf("expected %d violations, got %d: %v", len(want), len(report.Violations), report.Violations)
+	}
+	for i, v := range report.Violations {
+		if v.Type != config.ViolationAlphabetical || v.Message != want[i] {
+			t.Errorf("violation %d = %s (%v), want %q", i, v.Message, v.Type, want[i])
+		}
+	}
+
+	sm := detector.NewDetector(fset, cfg).CollectStructMethods(file)["S"]
+	var names []string
+	for _, m := range sm.GetExpectedOrder() {
+		names = append(names, m.Name)
+	}
+	if got := strings.Join(names, " "); got != "MustS NewS Close Run Stop a b" {
+		t.Errorf("expected order = %q", got)
+	}
+}
+
+func TestDetect_IgnoreDirectives(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{
+			name: "no directive",
+			src: `package p
+type S struct{}
+func (s *S) stop()  {}
+func (s *S) Start() {}`,
+			want: 1,
+		},
+		{
+			name: "nolint on struct",
+			src: `package p
+//nolint:funcorder // lifecycle order
+type S struct{}
+func (s *S) stop()  {}
+func (s *S) Start() {}`,
+		},
+		{
+			name: "ignore on struct line",
+			src: `package p
+type S struct{} //funcorder:ignore
+func (s *S) stop()  {}
+func (s *S) Start() {}`,
+		},
+		{
+			name: "nolint all on method",
+			src: `package p
+type S struct{}
+// stop is called last.
+//nolint:all
+func (s *S) stop()  {}
+func (s *S) Start() {}`,
+		},
+		{
+			name: "bare nolint on method line",
+			src: `package p
+type S struct{}
+func (s *S) stop()  {} //nolint
+func (s *S) Start() {}`,
+		},
+		{
+			name: "whole file",
+			src: `//funcorder:ignore
+package p
+type S struct{}
+func (s *S) stop()  {}
+func (s *S) Start() {}`,
+		},
+		{
+			name: "other linter",
+			src: `package p
+type S struct{}
+//nolint:funlen,gocyclo
+func (s *S) stop()  {}
+func (s *S) Start() {}`,
+			want: 1,
+		},
+		{
+			name: "ignored method between others",
+			src: `package p
+type S struct{}
+func (s *S) close() {}
+func (s *S) open()  {} //nolint:funcorder
+func (s *S) Start() {}`,
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, fset := parseSource(t, tt.src)
+			cfg := config.DefaultConfig()
+			report := detector.NewDetector(fset, cfg).Detect(file, "test.go")
+			if len(report.Violations) != tt.want {
+				t.Errorf("expected %d violations, got %v", tt.want, report.Violations)
+			}
+
+			// --no-ignore reports the suppressed violations as well.
+			cfg.NoIgnore = true
+			if report := detector.NewDetector(fset, cfg).Detect(file, "test.go"); len(report.Violations) == 0 {
+				t.Error("expected violations with NoIgnore")
+			}
+		})
+	}
+}
+
+func TestGetExpectedOrder_Ignored(t *testing.T) {
+	const src = `package p
+type S struct{}
+func (s *S) stop()  {}
+func (s *S) init()  {} //funcorder:ignore
+func (s *S) Start() {}
+func (s *S) Wait()  {}`
+
+	file, fset := parseSource(t, src)
+	sm := detector.NewDetector(fset, config.DefaultConfig()).CollectStructMethods(file)["S"]
+
+	var names []string
+	for _, m := range sm.GetExpectedOrder() {
+		names = append(names, m.Name)
+	}
+	// init keeps its slot; the other methods are ordered around it.
+	if got := strings.Join(names, " "); got != "Start init Wait stop" {
+		t.Errorf("expected order = %q", got)
+	}
+}
+
 func TestDetect_SingleMethod(t *testing.T) {
 	const src = `package p
 type S struct{}
@@ -0,0 +1,99 @@
+package detector
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// ignoreDirective is the dedicated directive that suppresses funcorder-fix.
+const ignoreDirective = "//funcorder:ignore"
+
+// directives records where the suppression directives of a file are.
+type directives struct {
+	fset *token.FileSet
+
+	// disabled turns every directive off.
+	disabled bool
+
+	// file reports whether a directive before the package clause suppresses
+	// the whole file.
+	file bool
+
+	// lines holds the lines of the file that carry a directive.
+	lines map[int]bool
+}
+
+// newDirectives collects the suppression directives of file. When disabled is
+// true the result ignores nothing, so that suppressed items can be audited.
+func newDirectives(fset *token.FileSet, file *ast.File, disabled bool) *directives {
+	dirs := &directives{fset: fset, disabled: disabled, lines: make(map[int]bool)}
+	if disabled {
+		return dirs
+	}
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if !isDirective(c.Text) {
+				continue
+			}
+			if c.Pos() < file.Package {
+				dirs.file = true
+			}
+			dirs.lines[fset.Position(c.Pos()).Line] = true
+		}
+	}
+	return dirs
+}
+
+// ignores reports whether a directive suppresses the declaration starting at
+// pos with the doc comments docs: the file is suppressed, one of the doc
+// comments holds a directive, or a comment on the first line of the
+// declaration does.
+func (dirs *directives) ignores(pos token.Pos, docs ...*ast.CommentGroup) bool {
+	if dirs.disabled {
+		return false
+	}
+	if dirs.file {
+		return true
+	}
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		for _, c := range doc.List {
+			if isDirective(c.Text) {
+				return true
+			}
+		}
+	}
+	return len(dirs.lines) > 0 && dirs.lines[dirs.fset.Position(pos).Line]
+}
+
+// isDirective reports whether the comment text is //funcorder:ignore or a
+// golangci-lint //nolint directive covering funcorder: without a linter list,
+// or with a list naming funcorder or all. An explanation may follow after
+// another //.
+func isDirective(text string) bool {
+	if i := strings.Index(text[2:], "//"); i >= 0 {
+		text = text[:i+2]
+	}
+	text = strings.TrimSpace(text)
+
+	if text == ignoreDirective {
+		return true
+	}
+	if text == "//nolint" {
+		return true
+	}
+	linters, ok := strings.CutPrefix(text, "//nolint:")
+	if !ok {
+		return false
+	}
+	for _, name := range strings.Split(linters, ",") {
+		switch strings.TrimSpace(name) {
+		case "funcorder", "all":
+			return true
+		}
+	}
+	return false
+}
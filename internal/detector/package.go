@@ -0,0 +1,311 @@
+package detector
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+)
+
+// knownOSArch lists GOOS and GOARCH values that act as implicit build
+// constraints when used as a file name suffix (e.g. conn_windows.go).
+var knownOSArch = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+	"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+	"windows": true, "zos": true,
+	"386": true, "amd64": true, "arm": true, "arm64": true, "loong64": true,
+	"mips": true, "mipsle": true, "mips64": true, "mips64le": true, "ppc64": true,
+	"ppc64le": true, "riscv64": true, "s390x": true, "wasm": true,
+}
+
+// DetectPackage analyzes all files of a single package together and returns
+// one Report per file, in the same order as files. Methods are grouped by
+// struct across the whole package, and ordering is checked among the methods
+// each file declares. When MoveMethods is enabled, methods declared outside
+// the file of their struct are reported as well.
+func (d *Detector) DetectPackage(files []*ast.File, paths []string) []*Report {
+	structs := d.CollectPackageStructMethods(files)
+
+	reports := make([]*Report, len(files))
+	for i, file := range files {
+		report := &Report{
+			FilePath:   paths[i],
+			Violations: []*Violation{},
+		}
+
+		for _, sm := range structs {
+			if fileMethods := sm.InFile(file); fileMethods != nil {
+				d.checkStructMethods(fileMethods, report)
+			}
+		}
+
+		if d.config.MoveMethods {
+			d.checkMisplacedMethods(structs, files, file, report)
+		}
+
+		if d.config.StandaloneConstructors {
+			d.checkStandaloneConstructors(file, structs, report)
+		}
+
+		report.sort()
+		reports[i] = report
+	}
+
+	return reports
+}
+
+// CollectPackageStructMethods collects the methods of every struct declared in
+// the package, grouping methods by receiver type across all files.
+func (d *Detector) CollectPackageStructMethods(files []*ast.File) map[string]*StructMethods {
+	return d.collectStructMethodsFrom(files)
+}
+
+// MisplacedMethods returns the methods declared in a file other than the one
+// declaring their struct that can be moved there safely, sorted by position.
+// Ignored methods are never returned.
+// files must contain every file of the package.
+func (d *Detector) MisplacedMethods(structs map[string]*StructMethods, files []*ast.File) []*MethodInfo {
+	names := make([]string, 0, len(structs))
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// removed tracks import uses taken out of each file by earlier moves, so
+	// that moving several methods never leaves an unused import behind.
+	removed := make(map[*ast.File]map[string]int)
+
+	var misplaced []*MethodInfo
+	for _, name := range names {
+		sm := structs[name]
+		home := FileContaining(files, sm.StructPos)
+		if home == nil {
+			continue
+		}
+
+		for _, m := range sm.Methods {
+			from := FileContaining(files, m.Pos)
+			if m.Ignored || from == nil || from == home {
+				continue
+			}
+			if removed[from] == nil {
+				removed[from] = make(map[string]int)
+			}
+			if d.canRelocate(m.FuncDecl, from, home, removed[from]) {
+				for base, n := range selectorBases(m.FuncDecl) {
+					removed[from][base] += n
+				}
+				misplaced = append(misplaced, m)
+			}
+		}
+	}
+
+	sort.Slice(misplaced, func(i, j int) bool {
+		return misplaced[i].Pos < misplaced[j].Pos
+	})
+	return misplaced
+}
+
+// InFile returns a StructMethods holding only the methods declared in file,
+// categorized on their own. It returns nil if file declares no such methods.
+func (sm *StructMethods) InFile(file *ast.File) *StructMethods {
+	var methods []*MethodInfo
+	for _, m := range sm.Methods {
+		if FileContaining([]*ast.File{file}, m.Pos) == file {
+			methods = append(methods, m)
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+
+	fileMethods := &StructMethods{
+		StructName: sm.StructName,
+		StructPos:  sm.StructPos,
+		StructEnd:  sm.StructEnd,
+		Methods:    methods,
+
+		Alphabetical:    sm.Alphabetical,
+		MixedVisibility: sm.MixedVisibility,
+	}
+	fileMethods.CategorizeMethods()
+	return fileMethods
+}
+
+// checkMisplacedMethods reports methods declared in file whose struct is
+// declared in another file of the package.
+func (d *Detector) checkMisplacedMethods(structs map[string]*StructMethods, files []*ast.File, file *ast.File, report *Report) {
+	for _, m := range d.MisplacedMethods(structs, files) {
+		if FileContaining(files, m.Pos) != file {
+			continue
+		}
+		sm := structs[m.ReceiverType]
+		homeFile := filepath.Base(d.fset.Position(sm.StructPos).Filename)
+		report.AddViolation(newViolation(
+			config.ViolationMisplaced,
+			d.fset,
+			m.FuncDecl,
+			sm.StructName,
+			fmt.Sprintf("method %s should be declared in %s next to struct %s",
+				m.Name, homeFile, sm.StructName),
+			SuggestedFix{
+				TargetPos:  sm.StructEnd,
+				TargetName: sm.StructName,
+			},
+		))
+	}
+}
+
+// canRelocate reports whether fn can be moved from one file to another without
+// changing which builds include it or breaking imports in either file.
+// removed holds the import uses already moved out of from.
+func (d *Detector) canRelocate(fn *ast.FuncDecl, from, to *ast.File, removed map[string]int) bool {
+	if d.isConstrained(from) || d.isConstrained(to) {
+		return false
+	}
+
+	fromImports, ok := importsByName(from)
+	if !ok {
+		return false
+	}
+	toImports, ok := importsByName(to)
+	if !ok {
+		return false
+	}
+
+	used := selectorBases(fn)
+	if len(used) == 0 {
+		return true
+	}
+	for name, path := range fromImports {
+		if path == "" {
+			// The package name cannot be derived reliably from the path.
+			return false
+		}
+		if used[name] == 0 {
+			continue
+		}
+		if toImports[name] != path {
+			return false
+		}
+		// The source file must still use the import once fn is gone.
+		if selectorBases(from)[name] <= removed[name]+used[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// isConstrained reports whether file is a test file or only takes part in
+// some builds, either through a build constraint comment or its file name.
+func (d *Detector) isConstrained(file *ast.File) bool {
+	name := filepath.Base(d.fset.Position(file.Package).Filename)
+	if strings.HasSuffix(name, "_test.go") {
+		return true
+	}
+
+	parts := strings.Split(strings.TrimSuffix(name, ".go"), "_")
+	for _, part := range parts[1:] {
+		if knownOSArch[part] {
+			return true
+		}
+	}
+
+	for _, cg := range file.Comments {
+		if cg.Pos() > file.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if strings.HasPrefix(c.Text, "//go:build") || strings.HasPrefix(c.Text, "// +build") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// importsByName maps the local name of each import in file to its path.
+// The path is empty when the name cannot be derived from it reliably.
+// It returns false if file has a dot import, whose uses cannot be tracked.
+func importsByName(file *ast.File) (map[string]string, bool) {
+	imports := make(map[string]string, len(file.Imports))
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, false
+		}
+		if spec.Name != nil {
+			switch spec.Name.Name {
+			case ".":
+				return nil, false
+			case "_":
+				continue
+			}
+			imports[spec.Name.Name] = path
+			continue
+		}
+
+		name, ok := guessPackageName(path)
+		if !ok {
+			imports[name] = ""
+			continue
+		}
+		imports[name] = path
+	}
+	return imports, true
+}
+
+// guessPackageName derives the conventional package name from an import path.
+func guessPackageName(path string) (string, bool) {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	return name, name != "" && !strings.ContainsAny(name, "-.")
+}
+
+// isMajorVersion reports whether s looks like a module major version suffix (v2, v10).
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(s[1:])
+	return err == nil
+}
+
+// selectorBases counts the unresolved identifiers used as the base of a
+// selector expression (pkg.Name) in node. These are candidate import uses.
+func selectorBases(node ast.Node) map[string]int {
+	bases := make(map[string]int)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				bases[ident.Name]++
+			}
+		}
+		return true
+	})
+	return bases
+}
+
+// FileContaining returns the file of files whose range contains pos, or nil.
+func FileContaining(files []*ast.File, pos token.Pos) *ast.File {
+	for _, file := range files {
+		if pos >= file.FileStart && pos <= file.FileEnd {
+			return file
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package detector
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+)
+
+func parsePackage(t *testing.T, sources map[string]string, names ...string) (*token.FileSet, []*ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	files := make([]*ast.File, len(names))
+	for i, name := range names {
+		file, err := parser.ParseFile(fset, name, sources[name], parser.ParseComments)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		files[i] = file
+	}
+	return fset, files
+}
+
+func TestDetectPackage_MethodsAcrossFiles(t *testing.T) {
+	sources := map[string]string{
+		"a.go": "package p\ntype S struct{}\nfunc (s *S) Run() {}\n",
+		"b.go": "package p\nfunc (s *S) helper() {}\nfunc (s *S) Stop() {}\n",
+	}
+	fset, files := parsePackage(t, sources, "a.go", "b.go")
+
+	d := NewDetector(fset, config.DefaultConfig())
+	reports := d.DetectPackage(files, []string{"a.go", "b.go"})
+
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+	if reports[0].HasViolations() {
+		t.Errorf("a.go: expected no violations, got %v", reports[0].Violations)
+	}
+	if len(reports[1].Violations) != 1 || reports[1].Violations[0].MethodName != "helper" {
+		t.Errorf("b.go: expected a single violation for helper, got %v", reports[1].Violations)
+	}
+}
+
+func TestDetectPackage_Misplaced(t *testing.T) {
+	sources := map[string]string{
+		"a.go":        "package p\ntype S struct{}\nfunc (s *S) Run() {}\n",
+		"b.go":        "package p\nfunc (s *S) Stop() {}\n",
+		"b_linux.go":  "package p\nfunc (s *S) poll() {}\n",
+		"b_tagged.go": "//go:build debug\n\npackage p\nfunc (s *S) dump() {}\n",
+	}
+	fset, files := parsePackage(t, sources, "a.go", "b.go", "b_linux.go", "b_tagged.go")
+
+	cfg := config.DefaultConfig()
+	cfg.MoveMethods = true
+	d := NewDetector(fset, cfg)
+	reports := d.DetectPackage(files, []string{"a.go", "b.go", "b_linux.go", "b_tagged.go"})
+
+	if len(reports[1].Violations) != 1 || reports[1].Violations[0].Type != config.ViolationMisplaced {
+		t.Errorf("b.go: expected one misplaced violation, got %v", reports[1].Violations)
+	}
+	if reports[2].HasViolations() {
+		t.Errorf("b_linux.go: platform-specific methods must stay, got %v", reports[2].Violations)
+	}
+	if reports[3].HasViolations() {
+		t.Errorf("b_tagged.go: constrained methods must stay, got %v", reports[3].Violations)
+	}
+}
+
+func TestMisplacedMethods_Imports(t *testing.T) {
+	sources := map[string]string{
+		"a.go": "package p\nimport \"strings\"\ntype S struct{ n string }\nfunc (s *S) Upper() string { return strings.ToUpper(s.n) }\n",
+		"b.go": "package p\nimport (\n\t\"fmt\"\n\t\"strings\"\n)\n" +
+			"func (s *S) Print() { fmt.Println(s.n) }\n" +
+			"func (s *S) Lower() string { return strings.ToLower(s.n) }\n" +
+			"func (s *S) Trim() string { return strings.TrimSpace(s.n) }\n",
+	}
+	fset, files := parsePackage(t, sources, "a.go", "b.go")
+
+	d := NewDetector(fset, config.DefaultConfig())
+	misplaced := d.MisplacedMethods(d.CollectPackageStructMethods(files), files)
+
+	// Print needs fmt, which a.go does not import. Lower can move, but Trim
+	// must stay so that b.go keeps using strings.
+	if len(misplaced) != 1 || misplaced[0].Name != "Lower" {
+		names := make([]string, len(misplaced))
+		for i, m := range misplaced {
+			names[i] = m.Name
+		}
+		t.Errorf("expected only Lower to be movable, got %v", names)
+	}
+}
+
+func TestGuessPackageName(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"fmt", "fmt", true},
+		{"net/http", "http", true},
+		{"github.com/google/uuid", "uuid", true},
+		{"gopkg.in/yaml.v3", "yaml", true},
+		{"github.com/jackc/pgx/v5", "pgx", true},
+		{"github.com/mattn/go-sqlite3", "sqlite3", true},
+		{"github.com/foo/bar-baz", "bar-baz", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := guessPackageName(tt.path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("guessPackageName(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
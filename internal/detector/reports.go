@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"sort"
+	"strings"
 
 	"github.com/vajrock/funcorder-fix/internal/config"
 )
@@ -47,6 +49,27 @@ func (v *Violation) String() string {
 	return fmt.Sprintf("%s: %s", v.Position, v.Message)
 }
 
+// StructOrder describes the current and expected method order of a struct.
+type StructOrder struct {
+	// StructName is the name of the struct.
+	StructName string
+
+	// Position is the position of the first method of the struct in the file.
+	Position token.Position
+
+	// Current lists the method names in their current order.
+	Current []string
+
+	// Expected lists the method names in the order the fixer produces.
+	Expected []string
+}
+
+// String returns a human-readable representation of the expected order.
+func (o *StructOrder) String() string {
+	return fmt.Sprintf("%s: expected method order for %s: %s",
+		o.Position, o.StructName, strings.Join(o.Expected, ", "))
+}
+
 // Report contains all violations found in a file.
 type Report struct {
 	// FilePath is the path to the file being analyzed.
@@ -54,6 +77,14 @@ type Report struct {
 
 	// Violations is a list of all violations found.
 	Violations []*Violation
+
+	// Orders lists the current and expected method order of every struct
+	// with violations, sorted by position.
+	Orders []*StructOrder
+
+	// Structs lists the method order of every struct with methods in the
+	// file, sorted by position.
+	Structs []*StructOrder
 }
 
 // HasViolations returns true if there are any violations.
@@ -66,6 +97,34 @@ func (r *Report) AddViolation(v *Violation) {
 	r.Violations = append(r.Violations, v)
 }
 
+// sort orders the violations, struct orders and structs of r by position.
+func (r *Report) sort() {
+	sort.Slice(r.Violations, func(i, j int) bool {
+		return r.Violations[i].MethodPos < r.Violations[j].MethodPos
+	})
+	sort.Slice(r.Orders, func(i, j int) bool {
+		return r.Orders[i].Position.Offset < r.Orders[j].Position.Offset
+	})
+	sort.Slice(r.Structs, func(i, j int) bool {
+		return r.Structs[i].Position.Offset < r.Structs[j].Position.Offset
+	})
+}
+
+// newStructOrder creates a StructOrder describing the methods of sm.
+func newStructOrder(fset *token.FileSet, sm *StructMethods) *StructOrder {
+	order := &StructOrder{
+		StructName: sm.StructName,
+		Position:   fset.Position(sm.Methods[0].Pos),
+	}
+	for _, m := range sm.GetCurrentOrder() {
+		order.Current = append(order.Current, m.Name)
+	}
+	for _, m := range sm.GetExpectedOrder() {
+		order.Expected = append(order.Expected, m.Name)
+	}
+	return order
+}
+
 // newViolation creates a new Violation with the given parameters.
 func newViolation(
 	vtype config.ViolationType,
@@ -1,6 +1,7 @@
 package detector
 
 import (
+	"go/parser"
 	"go/token"
 	"strings"
 	"testing"
@@ -61,3 +62,82 @@ func TestViolation_String(t *testing.T) {
 		t.Errorf("expected String() to contain message, got %q", s)
 	}
 }
+
+func TestStructOrder_String(t *testing.T) {
+	o := &StructOrder{
+		StructName: "Svc",
+		Position:   token.Position{Filename: "foo.go", Line: 5, Column: 1},
+		Current:    []string{"helper", "Run", "NewSvc"},
+		Expected:   []string{"NewSvc", "Run", "helper"},
+	}
+
+	want := "foo.go:5:1: expected method order for Svc: NewSvc, Run, helper"
+	if got := o.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestDetect_Orders(t *testing.T) {
+	const src = `package p
+type Ok struct{}
+func (o *Ok) Run() {}
+func (o *Ok) stop() {}
+type Svc struct{}
+func (s *Svc) helper() {}
+func (s *Svc) Run() {}
+func (s *Svc) NewSvc() *Svc { return s }`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := NewDetector(fset, config.DefaultConfig()).Detect(file, "test.go")
+
+	if len(report.Orders) != 1 {
+		t.Fatalf("expected 1 struct order, got %d", len(report.Orders))
+	}
+	order := report.Orders[0]
+	if order.StructName != "Svc" || order.Position.Line != 6 {
+		t.Errorf("unexpected order %s for %s", order.Position, order.StructName)
+	}
+	if got := strings.Join(order.Current, " "); got != "helper Run NewSvc" {
+		t.Errorf("Current = %q", got)
+	}
+	if got := strings.Join(order.Expected, " "); got != "NewSvc Run helper" {
+		t.Errorf("Expected = %q", got)
+	}
+
+	// Structs lists every struct with methods, including well-ordered ones.
+	if len(report.Structs) != 2 {
+		t.Fatalf("expected 2 structs, got %d", len(report.Structs))
+	}
+	if report.Structs[0].StructName != "Ok" || report.Structs[1] != order {
+		t.Errorf("unexpected structs %s, %s", report.Structs[0].StructName, report.Structs[1].StructName)
+	}
+}
+
+func TestDetect_StructOrderDisabledRule(t *testing.T) {
+	const src = `package p
+type Svc struct{}
+func (s *Svc) run() {}
+func (s *Svc) Run() {}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.CheckExported = false
+	report := NewDetector(fset, cfg).Detect(file, "test.go")
+
+	// The expected order only follows the enabled rules, so it matches the
+	// current one when nothing is reported.
+	if report.HasViolations() || len(report.Structs) != 1 {
+		t.Fatalf("expected 1 struct without violations, got %v", report.Violations)
+	}
+	if got := strings.Join(report.Structs[0].Expected, " "); got != "run Run" {
+		t.Errorf("Expected = %q, want %q", got, "run Run")
+	}
+}
@@ -3,7 +3,12 @@ package detector
 import (
 	"go/ast"
 	"go/token"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
 )
 
 // MethodInfo holds information about a single method.
@@ -17,7 +22,9 @@ type MethodInfo struct {
 	// IsExported indicates if the method is exported (public).
 	IsExported bool
 
-	// IsConstructor indicates if this is a constructor (New*, Must*, Or*).
+	// IsConstructor indicates if this is a constructor (by default New*,
+	// Must*, Or*). Methods are only marked as constructors when the
+	// constructor rule is enabled.
 	IsConstructor bool
 
 	// ReceiverType is the receiver type name for methods, empty for functions.
@@ -31,6 +38,10 @@ type MethodInfo struct {
 
 	// DocComment is the documentation comment group (if any).
 	DocComment *ast.CommentGroup
+
+	// Ignored indicates that a //nolint:funcorder or //funcorder:ignore
+	// directive suppresses the method, so it keeps its position.
+	Ignored bool
 }
 
 // StructMethods holds information about all methods of a struct.
@@ -47,7 +58,8 @@ type StructMethods struct {
 	// Methods is a list of all methods belonging to this struct.
 	Methods []*MethodInfo
 
-	// Constructors are methods that are constructors (New*, Must*, Or*).
+	// Constructors are methods that are constructors (by default New*,
+	// Must*, Or*).
 	Constructors []*MethodInfo
 
 	// ExportedMethods are public methods (excluding constructors).
@@ -55,19 +67,35 @@ type StructMethods struct {
 
 	// UnexportedMethods are private methods.
 	UnexportedMethods []*MethodInfo
+
+	// StandaloneConstructors are receiver-less constructor functions declared
+	// in the same file as the struct and returning it, in source order.
+	StandaloneConstructors []*MethodInfo
+
+	// Alphabetical sorts each group of methods by name in the expected order
+	// instead of keeping their source order.
+	Alphabetical bool
+
+	// MixedVisibility keeps exported and unexported methods interleaved as
+	// in the source in the expected order, instead of placing the exported
+	// ones first, for when that rule is disabled.
+	MixedVisibility bool
+
+	// Ignored indicates that a directive on the struct declaration or in
+	// its file suppresses the struct, so all of its methods are ignored.
+	Ignored bool
 }
 
 // newMethodInfo creates a MethodInfo from an ast.FuncDecl.
 func newMethodInfo(fn *ast.FuncDecl) *MethodInfo {
 	name := fn.Name.Name
 	info := &MethodInfo{
-		FuncDecl:    fn,
-		Name:        name,
-		IsExported:  ast.IsExported(name),
-		IsConstructor: isConstructor(name),
-		Pos:         fn.Pos(),
-		End:         fn.End(),
-		DocComment:  fn.Doc,
+		FuncDecl:   fn,
+		Name:       name,
+		IsExported: ast.IsExported(name),
+		Pos:        fn.Pos(),
+		End:        fn.End(),
+		DocComment: fn.Doc,
 	}
 
 	// Extract receiver type if this is a method
@@ -95,17 +123,49 @@ func GetReceiverTypeName(expr ast.Expr) string {
 	return ""
 }
 
+// constructorMatcher classifies function/method names as constructors by the
+// prefixes and regular expressions configured in config.Config.
+type constructorMatcher struct {
+	prefixes []string
+	patterns []*regexp.Regexp
+}
+
+// newConstructorMatcher creates a constructorMatcher from cfg. Patterns that
+// do not compile are ignored; config.Config.Validate reports them.
+func newConstructorMatcher(cfg *config.Config) *constructorMatcher {
+	m := &constructorMatcher{prefixes: cfg.ConstructorPrefixes}
+	for _, pattern := range cfg.ConstructorPatterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			m.patterns = append(m.patterns, re)
+		}
+	}
+	return m
+}
+
 // isConstructor checks if a function/method name matches constructor patterns.
-// Constructors are functions that start with New, Must, or Or.
-func isConstructor(name string) bool {
-	return strings.HasPrefix(name, "New") ||
-		strings.HasPrefix(name, "Must") ||
-		strings.HasPrefix(name, "Or")
+// Constructors are functions that start with one of the prefixes or match one
+// of the regular expressions.
+func (m *constructorMatcher) isConstructor(name string) bool {
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
 }
 
 // CategorizeMethods separates methods into constructors, exported, and unexported.
+// Ignored methods belong to none of the groups.
 func (sm *StructMethods) CategorizeMethods() {
 	for _, m := range sm.Methods {
+		if m.Ignored {
+			continue
+		}
 		if m.IsConstructor {
 			sm.Constructors = append(sm.Constructors, m)
 		} else if m.IsExported {
@@ -117,13 +177,38 @@ func (sm *StructMethods) CategorizeMethods() {
 }
 
 // GetExpectedOrder returns methods in the expected order:
-// Constructors → Exported → Unexported
+// Constructors → Exported → Unexported, each group sorted by name when
+// Alphabetical is set. With MixedVisibility, exported and unexported methods
+// keep their relative positions and are only sorted among the positions of
+// their own group. Ignored methods keep their position, and the other
+// methods fill the remaining positions.
 func (sm *StructMethods) GetExpectedOrder() []*MethodInfo {
 	result := make([]*MethodInfo, 0, len(sm.Methods))
-	result = append(result, sm.Constructors...)
-	result = append(result, sm.ExportedMethods...)
-	result = append(result, sm.UnexportedMethods...)
-	return result
+	for _, group := range sm.groups() {
+		start := len(result)
+		result = append(result, group...)
+		if sm.Alphabetical {
+			sortByName(result[start:])
+		}
+	}
+	if sm.MixedVisibility {
+		sm.interleave(result[len(sm.Constructors):])
+	}
+	if !slices.ContainsFunc(sm.Methods, func(m *MethodInfo) bool { return m.Ignored }) {
+		return result
+	}
+
+	pinned := make([]*MethodInfo, len(sm.Methods))
+	next := 0
+	for i, m := range sm.Methods {
+		if m.Ignored {
+			pinned[i] = m
+			continue
+		}
+		pinned[i] = result[next]
+		next++
+	}
+	return pinned
 }
 
 // GetCurrentOrder returns methods in their current order (sorted by position).
@@ -153,3 +238,37 @@ func (sm *StructMethods) NeedsReordering() bool {
 
 	return false
 }
+
+// groups returns the constructors, exported and unexported methods, each in
+// source order.
+func (sm *StructMethods) groups() [][]*MethodInfo {
+	return [][]*MethodInfo{sm.Constructors, sm.ExportedMethods, sm.UnexportedMethods}
+}
+
+// interleave rearranges methods, the exported methods followed by the
+// unexported ones, so that each method takes the place of a method of its
+// group in source order.
+func (sm *StructMethods) interleave(methods []*MethodInfo) {
+	exported := slices.Clone(methods[:len(sm.ExportedMethods)])
+	unexported := slices.Clone(methods[len(sm.ExportedMethods):])
+	i := 0
+	for _, m := range sm.Methods {
+		if m.Ignored || m.IsConstructor {
+			continue
+		}
+		if m.IsExported {
+			methods[i], exported = exported[0], exported[1:]
+		} else {
+			methods[i], unexported = unexported[0], unexported[1:]
+		}
+		i++
+	}
+}
+
+// sortByName sorts methods by name, keeping the source order of methods with
+// the same name.
+func sortByName(methods []*MethodInfo) {
+	sort.SliceStable(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"strings"
 	"testing"
 
 	"github.com/vajrock/funcorder-fix/internal/config"
@@ -77,9 +78,42 @@ func TestIsConstructor(t *testing.T) {
 		{"Newsroom", true}, // false positive by design (HasPrefix "New")
 	}
 
+	m := newConstructorMatcher(config.DefaultConfig())
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := isConstructor(tt.name)
+			got := m.isConstructor(tt.name)
+			if got != tt.want {
+				t.Errorf("isConstructor(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConstructor_Configured(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ConstructorPrefixes = []string{"New", "Open", "Make", "From"}
+	cfg.ConstructorPatterns = []string{`^With[A-Z]`, `(`}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"NewSnapshot", true},
+		{"OpenFile", true},
+		{"MakeBuffer", true},
+		{"FromJSON", true},
+		{"WithTimeout", true},
+		{"Without", false},   // pattern requires an upper-case letter after With
+		{"OrDefault", false}, // Or is no longer a prefix
+		{"MustValidate", false},
+		{"Order", false},
+	}
+
+	// The invalid pattern "(" is ignored.
+	m := newConstructorMatcher(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.isConstructor(tt.name)
 			if got != tt.want {
 				t.Errorf("isConstructor(%q) = %v, want %v", tt.name, got, tt.want)
 			}
@@ -113,6 +147,40 @@ func TestGetExpectedOrder(t *testing.T) {
 	}
 }
 
+func TestGetExpectedOrder_MixedVisibility(t *testing.T) {
+	methods := []*MethodInfo{
+		{Name: "zeta", IsExported: false},
+		{Name: "Stop", IsExported: true},
+		{Name: "NewFoo", IsConstructor: true, IsExported: true},
+		{Name: "alpha", IsExported: false},
+		{Name: "Run", IsExported: true},
+	}
+	tests := []struct {
+		alphabetical bool
+		want         string
+	}{
+		{false, "NewFoo zeta Stop alpha Run"},
+		{true, "NewFoo alpha Run zeta Stop"},
+	}
+	for _, tt := range tests {
+		sm := &StructMethods{
+			StructName:      "Foo",
+			Methods:         methods,
+			Alphabetical:    tt.alphabetical,
+			MixedVisibility: true,
+		}
+		sm.CategorizeMethods()
+
+		var got []string
+		for _, m := range sm.GetExpectedOrder() {
+			got = append(got, m.Name)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("alphabetical=%v: GetExpectedOrder() = %v, want %s", tt.alphabetical, got, tt.want)
+		}
+	}
+}
+
 func TestNeedsReordering_EdgeCases(t *testing.T) {
 	t.Run("zero_methods", func(t *testing.T) {
 		sm := &StructMethods{Methods: []*MethodInfo{}}
@@ -0,0 +1,117 @@
+package detector
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// TypedConstructors maps function keys (see FuncKey) to the name of the type
+// each function constructs according to type information. Functions known not
+// to be constructors map to "". Functions missing from the map, e.g. because
+// their file does not type-check, fall back to the name-based rule.
+type TypedConstructors map[string]string
+
+// ResolveConstructors classifies every function and method declared in files
+// using info. A function is a constructor of T when its results are T or *T,
+// optionally followed by error or bool, and T is a named type declared in the
+// same package. A method only counts as a constructor of its receiver type.
+// Files for which skip returns true are left out, so their functions keep the
+// name-based rule; skip may be nil.
+func ResolveConstructors(files []*ast.File, info *types.Info, skip func(*ast.File) bool) TypedConstructors {
+	tc := make(TypedConstructors)
+	for _, file := range files {
+		if skip != nil && skip(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			obj, ok := info.Defs[fn.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			tc[FuncKey(fn)] = constructedTypeOf(obj)
+		}
+	}
+	return tc
+}
+
+// FuncKey identifies a function within its package: "Recv.Name" for methods
+// and "Name" for functions.
+func FuncKey(fn *ast.FuncDecl) string {
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		return GetReceiverTypeName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// SetTypedConstructors makes the detector classify constructors from type
+// information instead of name prefixes wherever tc knows the function.
+func (d *Detector) SetTypedConstructors(tc TypedConstructors) {
+	d.constructors = tc
+}
+
+// isConstructorOf reports whether fn constructs the type typeName. Methods are
+// checked against their receiver type.
+func (d *Detector) isConstructorOf(fn *ast.FuncDecl, typeName string) bool {
+	if constructed, ok := d.constructors[FuncKey(fn)]; ok {
+		return constructed != "" && constructed == typeName
+	}
+	return d.matcher.isConstructor(fn.Name.Name)
+}
+
+// standaloneConstructedType returns the struct a receiver-less function
+// constructs, or "" if it is not a constructor.
+func (d *Detector) standaloneConstructedType(fn *ast.FuncDecl) string {
+	if constructed, ok := d.constructors[FuncKey(fn)]; ok {
+		return constructed
+	}
+	if !d.matcher.isConstructor(fn.Name.Name) {
+		return ""
+	}
+	return ConstructedTypeName(fn)
+}
+
+// constructedTypeOf returns the name of the type fn constructs, or "".
+func constructedTypeOf(fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return ""
+	}
+
+	results := sig.Results()
+	switch results.Len() {
+	case 1:
+	case 2:
+		second, ok := results.At(1).Type().(*types.Basic)
+		isError := types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type())
+		if !isError && (!ok || second.Kind() != types.Bool) {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	t := types.Unalias(results.At(0).Type())
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() != fn.Pkg() {
+		return ""
+	}
+
+	if recv := sig.Recv(); recv != nil {
+		recvType := recv.Type()
+		if ptr, ok := recvType.(*types.Pointer); ok {
+			recvType = ptr.Elem()
+		}
+		recvNamed, ok := recvType.(*types.Named)
+		if !ok || recvNamed.Obj() != named.Obj() {
+			return ""
+		}
+	}
+	return named.Obj().Name()
+}
@@ -0,0 +1,133 @@
+package detector_test
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/detector"
+)
+
+const typedSrc = `package p
+
+type Order struct{}
+
+func (o *Order) Order() int { return 0 }
+func (o *Order) Origin() string { return "" }
+func (o *Order) Mustache() bool { return false }
+func (o *Order) Build() *Order { return o }
+func (o Order) From() (Order, error) { return o, nil }
+
+type Conn struct{}
+
+func Open() (*Conn, error) { return nil, nil }
+func NewName() string { return "" }
+func Lookup() (*Conn, bool) { return nil, false }
+func Pair() (*Conn, *Conn) { return nil, nil }
+`
+
+// checkSource type-checks src and resolves its constructors.
+func checkSource(t *testing.T, src string) (*ast.File, *token.FileSet, detector.TypedConstructors) {
+	t.Helper()
+	file, fset := parseSource(t, src)
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	if _, err := (&types.Config{}).Check("p", fset, []*ast.File{file}, info); err != nil {
+		t.Fatalf("type check: %v", err)
+	}
+	return file, fset, detector.ResolveConstructors([]*ast.File{file}, info, nil)
+}
+
+func TestResolveConstructors(t *testing.T) {
+	_, _, tc := checkSource(t, typedSrc)
+
+	want := map[string]string{
+		"Order.Order":    "",
+		"Order.Origin":   "",
+		"Order.Mustache": "",
+		"Order.Build":    "Order",
+		"Order.From":     "Order",
+		"Open":           "Conn",
+		"Lookup":         "Conn",
+		"NewName":        "",
+		"Pair":           "",
+	}
+	for key, typeName := range want {
+		got, ok := tc[key]
+		if !ok {
+			t.Errorf("%s: not resolved", key)
+			continue
+		}
+		if got != typeName {
+			t.Errorf("%s: constructs %q, want %q", key, got, typeName)
+		}
+	}
+}
+
+func TestDetect_TypedConstructors(t *testing.T) {
+	file, fset, tc := checkSource(t, typedSrc)
+
+	d := detector.NewDetector(fset, config.DefaultConfig())
+	d.SetTypedConstructors(tc)
+	structs := d.CollectStructMethods(file)
+
+	var constructors []string
+	for _, m := range structs["Order"].Methods {
+		if m.IsConstructor {
+			constructors = append(constructors, m.Name)
+		}
+	}
+	if len(constructors) != 2 || constructors[0] != "Build" || constructors[1] != "From" {
+		t.Errorf("constructors of Order = %v, want [Build From]", constructors)
+	}
+
+	// Without type information the name prefixes decide.
+	structs = detector.NewDetector(fset, config.DefaultConfig()).CollectStructMethods(file)
+	constructors = nil
+	for _, m := range structs["Order"].Methods {
+		if m.IsConstructor {
+			constructors = append(constructors, m.Name)
+		}
+	}
+	if len(constructors) != 3 {
+		t.Errorf("prefix rule constructors of Order = %v, want [Order Origin Mustache]", constructors)
+	}
+}
+
+func TestDetect_TypedStandaloneConstructors(t *testing.T) {
+	file, fset, tc := checkSource(t, typedSrc)
+
+	cfg := config.DefaultConfig()
+	cfg.StandaloneConstructors = true
+	d := detector.NewDetector(fset, cfg)
+	d.SetTypedConstructors(tc)
+	report := d.Detect(file, "test.go")
+
+	// Open directly follows Conn; Lookup comes after NewName, which is not
+	// a constructor since it returns a string.
+	var names []string
+	for _, v := range report.Violations {
+		if v.Type == config.ViolationConstructor && v.StructName == "Conn" {
+			names = append(names, v.MethodName)
+		}
+	}
+	if len(names) != 1 || names[0] != "Lookup" {
+		t.Errorf("standalone constructor violations = %v, want [Lookup]", names)
+	}
+}
+
+func TestDetect_TypedConstructorsFallback(t *testing.T) {
+	file, fset := parseSource(t, `package p
+type S struct{}
+func (s *S) Run() {}
+func (s *S) NewCopy() *S { return s }`)
+
+	// Functions the type checker did not see keep the prefix rule.
+	d := detector.NewDetector(fset, config.DefaultConfig())
+	d.SetTypedConstructors(detector.TypedConstructors{})
+	report := d.Detect(file, "test.go")
+	if len(report.Violations) != 1 || report.Violations[0].MethodName != "NewCopy" {
+		t.Errorf("expected NewCopy to be reported by the prefix rule, got %v", report.Violations)
+	}
+}
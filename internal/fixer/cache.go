@@ -0,0 +1,87 @@
+package fixer
+
+import (
+	"encoding/json"
+
+	"github.com/vajrock/funcorder-fix/internal/cache"
+	"github.com/vajrock/funcorder-fix/internal/detector"
+)
+
+// cacheEntry is the cached result of processing a file.
+type cacheEntry struct {
+	Report       *detector.Report
+	Fixed        bool
+	FixedContent []byte
+	Replacements []Replacement
+}
+
+// SetCache makes the fixer store the result of every file it processes in c
+// and reuse it while the content of the file, the effective configuration
+// and the tool version are unchanged. Runs that type-check, with TypeCheck or
+// VerifyTypes, are not cached, as their results depend on other files.
+func (f *Fixer) SetCache(c *cache.Cache) {
+	f.cache = c
+}
+
+// cacheKey returns the cache key of the files at paths with the given
+// contents, or false if results are not cached.
+func (f *Fixer) cacheKey(paths []string, contents [][]byte) (string, bool) {
+	if f.cache == nil || f.config.TypeCheck || f.config.VerifyTypes {
+		return "", false
+	}
+
+	// Settings that only affect how results are output are left out, so
+	// that checking and fixing share entries.
+	cfg := *f.config
+	cfg.Write, cfg.Diff, cfg.List, cfg.Verbose = false, false, false, false
+	cfg.Jobs, cfg.Format = 0, ""
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", false
+	}
+
+	parts := [][]byte{data}
+	for i, path := range paths {
+		parts = append(parts, []byte(path), contents[i])
+	}
+	return f.cache.Key(parts...), true
+}
+
+// loadCached fills results from the entry for key and reports whether it was
+// found.
+func (f *Fixer) loadCached(key string, results []*Result) bool {
+	var entries []cacheEntry
+	if !f.cache.Get(key, &entries) || len(entries) != len(results) {
+		return false
+	}
+	for i, e := range entries {
+		if e.Report == nil {
+			return false
+		}
+		results[i].Report = e.Report
+		results[i].Violations = len(e.Report.Violations)
+		results[i].Fixed = e.Fixed
+		results[i].FixedContent = e.FixedContent
+		results[i].Replacements = e.Replacements
+	}
+	return true
+}
+
+// storeCached stores results as the entry for key unless one of them failed.
+// A cache that cannot be written only costs the next run time, so errors are
+// ignored.
+func (f *Fixer) storeCached(key string, results []*Result) {
+	entries := make([]cacheEntry, len(results))
+	for i, result := range results {
+		if result.Error != nil || result.Report == nil {
+			return
+		}
+		entries[i] = cacheEntry{
+			Report:       result.Report,
+			Fixed:        result.Fixed,
+			FixedContent: result.FixedContent,
+			Replacements: result.Replacements,
+		}
+	}
+	_ = f.cache.Put(key, entries)
+}
@@ -0,0 +1,50 @@
+package fixer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+)
+
+// placeConstructors moves the standalone constructors of file, read from
+// path, that are out of place, with their doc comments, directly after the
+// type declaration of the struct they return. Constructors keep their
+// relative source order. It returns src unchanged when every constructor is
+// already in place.
+func (f *Fixer) placeConstructors(fset *token.FileSet, path string, file *ast.File, src []byte) []byte {
+	det := f.newDetector(fset, path, file)
+	groups := det.StandaloneConstructorGroups(file, det.CollectStructMethods(file))
+
+	cp := NewCommentPreserver(fset, file)
+	var edits []Replacement
+	for _, group := range groups {
+		if group.InPlace {
+			continue
+		}
+
+		var text bytes.Buffer
+		for _, c := range group.Moved {
+			block := cp.GetMethodBlock(c.FuncDecl, src)
+			start, end := removalRange(src, fset.Position(block.StartPos).Offset, fset.Position(block.EndPos).Offset)
+			edits = append(edits, Replacement{
+				Start: start,
+				End:   end,
+			})
+
+			text.WriteString("\n\n")
+			text.WriteString(block.RawText)
+		}
+
+		anchor := fset.Position(group.Decl.End()).Offset
+		edits = append(edits, Replacement{
+			Start: anchor,
+			End:   anchor,
+			Text:  text.String(),
+		})
+	}
+
+	if len(edits) == 0 {
+		return src
+	}
+	return ApplyReplacements(src, mergeRemovals(src, edits))
+}
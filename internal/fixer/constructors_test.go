@@ -0,0 +1,116 @@
+package fixer_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/fixer"
+)
+
+func TestProcessFile_StandaloneConstructors(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	cfg.StandaloneConstructors = true
+
+	f := fixer.NewFixer(cfg)
+	result := f.ProcessFile(testdataPath("src", "standalone_constructors.go"))
+
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	// NewClient, MustClient and NewClientWithRetries are out of place.
+	if result.Violations != 3 {
+		t.Errorf("expected 3 violations, got %d", result.Violations)
+	}
+	if !result.Fixed {
+		t.Fatal("expected Fixed==true, got false")
+	}
+
+	golden, err := os.ReadFile(testdataPath("golden", "standalone_constructors.go"))
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if string(result.FixedContent) != string(golden) {
+		t.Errorf("FixedContent does not match golden file.\ngot:\n%s\nwant:\n%s",
+			result.FixedContent, golden)
+	}
+}
+
+func TestProcessFile_StandaloneConstructors_Idempotent(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	cfg.StandaloneConstructors = true
+
+	f := fixer.NewFixer(cfg)
+	result := f.ProcessFile(testdataPath("golden", "standalone_constructors.go"))
+
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if result.Violations != 0 {
+		t.Errorf("expected 0 violations on golden file, got %d", result.Violations)
+	}
+	if result.Fixed {
+		t.Error("expected Fixed==false on golden file")
+	}
+}
+
+func TestProcessFile_StandaloneConstructors_DisabledByDefault(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+
+	f := fixer.NewFixer(cfg)
+	result := f.ProcessFile(testdataPath("src", "standalone_constructors.go"))
+
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if result.Violations != 0 {
+		t.Errorf("expected 0 violations without standalone constructor mode, got %d", result.Violations)
+	}
+}
+
+func TestProcessSource_ConstructorsBeforeMethods(t *testing.T) {
+	const src = `package p
+
+type S struct{}
+
+const size = 1
+
+// NewS is in place: after S and before its methods.
+func NewS() *S { return &S{} }
+
+func (s *S) Run() {}
+
+// NewSized follows Run.
+func NewSized() *S { return &S{} }
+`
+	const want = `package p
+
+type S struct{}
+
+// NewSized follows Run.
+func NewSized() *S { return &S{} }
+
+const size = 1
+
+// NewS is in place: after S and before its methods.
+func NewS() *S { return &S{} }
+
+func (s *S) Run() {}
+`
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	cfg.StandaloneConstructors = true
+	cfg.ConstructorsBeforeMethods = true
+
+	result := fixer.NewFixer(cfg).ProcessSource("p.go", []byte(src))
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	// Only the constructor out of place is reported and moved.
+	if result.Violations != 1 || string(result.FixedContent) != want {
+		t.Errorf("got %d violations and\n%s\nwant 1 and\n%s", result.Violations, result.FixedContent, want)
+	}
+}
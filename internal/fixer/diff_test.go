@@ -0,0 +1,203 @@
+package fixer_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/fixer"
+)
+
+func TestFormatDiff_Output(t *testing.T) {
+	original := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	fixed := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nm\nn\n"
+
+	want := `--- a/dir/file.go
++++ b/dir/file.go
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -9,5 +9,5 @@
+ i
+ j
+ k
+-l
+ m
++n
+`
+	if got := fixer.FormatDiff("./dir/file.go", []byte(original), []byte(fixed)); got != want {
+		t.Errorf("FormatDiff() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatDiff_Equal(t *testing.T) {
+	if got := fixer.FormatDiff("file.go", []byte("a\n"), []byte("a\n")); got != "" {
+		t.Errorf("expected empty diff for equal contents, got %q", got)
+	}
+}
+
+func TestFormatDiff_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		fixed    string
+	}{
+		{"insert into empty", "", "a\nb\n"},
+		{"delete all", "a\nb\n", ""},
+		{"swap", "a\nb\n", "b\na\n"},
+		{"merged hunks", "1\n2\n3\n4\n5\n6\n7\n8\n", "1\nx\n3\n4\n5\n6\ny\n8\n"},
+		{"no trailing newline", "a\nb", "a\nc"},
+		{"add trailing newline", "a\nb", "a\nb\n"},
+		{"reorder", "p\n\nfunc a()\n\nfunc B()\n\nfunc c()\n", "p\n\nfunc B()\n\nfunc a()\n\nfunc c()\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := fixer.FormatDiff("f.go", []byte(tt.original), []byte(tt.fixed))
+			got, err := applyDiff(tt.original, diff)
+			if err != nil {
+				t.Fatalf("apply: %v\ndiff:\n%s", err, diff)
+			}
+			if got != tt.fixed {
+				t.Errorf("applying the diff gave %q, want %q\ndiff:\n%s", got, tt.fixed, diff)
+			}
+		})
+	}
+}
+
+func TestFormatDiff_GoldenFiles(t *testing.T) {
+	entries, err := os.ReadDir(testdataPath("src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		t.Run(entry.Name(), func(t *testing.T) {
+			src, err := os.ReadFile(testdataPath("src", entry.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden, err := os.ReadFile(testdataPath("golden", entry.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			diff := fixer.FormatDiff(entry.Name(), src, golden)
+			got, err := applyDiff(string(src), diff)
+			if err != nil {
+				t.Fatalf("apply: %v\ndiff:\n%s", err, diff)
+			}
+			if got != string(golden) {
+				t.Errorf("applying the diff does not reproduce the golden file\ndiff:\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestFormatDiff_Patch checks that patch -p1 accepts the output.
+func TestFormatDiff_Patch(t *testing.T) {
+	if _, err := exec.LookPath("patch"); err != nil {
+		t.Skip("patch not available")
+	}
+
+	src, err := os.ReadFile(testdataPath("src", "mixed_violations.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := os.ReadFile(testdataPath("golden", "mixed_violations.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pkg", "mixed.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("patch", "-p1", "--quiet")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(fixer.FormatDiff("pkg/mixed.go", src, golden))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("patch failed: %v\n%s", err, out)
+	}
+
+	patched, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(patched) != string(golden) {
+		t.Errorf("patched file does not match golden file:\n%s", patched)
+	}
+}
+
+// applyDiff applies a unified diff produced by FormatDiff to original. It
+// checks that context and deleted lines match.
+func applyDiff(original, diff string) (string, error) {
+	var src []string
+	if original != "" {
+		src = strings.SplitAfter(original, "\n")
+		if src[len(src)-1] == "" {
+			src = src[:len(src)-1]
+		}
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+	var out []string
+	pos := 0
+	for i := 2; i < len(lines) && lines[i] != ""; i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "@@") {
+			fields := strings.Fields(line)
+			start, _, _ := strings.Cut(strings.TrimPrefix(fields[1], "-"), ",")
+			n, err := strconv.Atoi(start)
+			if err != nil {
+				return "", err
+			}
+			if strings.HasSuffix(fields[1], ",0") {
+				n++
+			}
+			for pos < n-1 {
+				out = append(out, src[pos])
+				pos++
+			}
+			continue
+		}
+		if strings.HasPrefix(line, `\`) {
+			continue
+		}
+
+		// A line followed by the no-newline marker has no terminator.
+		text := line[1:]
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], `\`) {
+			text = strings.TrimSuffix(text, "\n")
+		}
+		switch line[0] {
+		case ' ', '-':
+			if pos >= len(src) || src[pos] != text {
+				return "", fmt.Errorf("line %d does not match %q", pos+1, text)
+			}
+			pos++
+			if line[0] == ' ' {
+				out = append(out, text)
+			}
+		case '+':
+			out = append(out, text)
+		}
+	}
+	out = append(out, src[pos:]...)
+	return strings.Join(out, ""), nil
+}
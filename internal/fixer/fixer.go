@@ -8,7 +8,9 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"sync"
 
+	"github.com/vajrock/funcorder-fix/internal/cache"
 	"github.com/vajrock/funcorder-fix/internal/config"
 	"github.com/vajrock/funcorder-fix/internal/detector"
 )
@@ -16,11 +18,32 @@ import (
 // Fixer orchestrates detection and fixing of funcorder violations.
 type Fixer struct {
 	config *config.Config
+
+	// configs resolves the configuration of each directory when set.
+	configs *config.Loader
+
+	// cache stores the results of processed files when set.
+	cache *cache.Cache
+
+	// mu guards typed, the type-checked constructors cached per directory,
+	// fixers, the fixers for the configurations resolved by configs,
+	// gitignores, the .gitignore rules cached per directory, and generated,
+	// the number of generated files skipped by directory walks.
+	mu         sync.Mutex
+	typed      map[string]map[string]detector.TypedConstructors
+	fixers     map[*config.Config]*Fixer
+	gitignores map[string][]gitignoreRule
+	generated  int
 }
 
 // NewFixer creates a new Fixer with the given configuration.
 func NewFixer(cfg *config.Config) *Fixer {
-	return &Fixer{config: cfg}
+	return &Fixer{
+		config:     cfg,
+		typed:      make(map[string]map[string]detector.TypedConstructors),
+		fixers:     make(map[*config.Config]*Fixer),
+		gitignores: make(map[string][]gitignoreRule),
+	}
 }
 
 // Result contains the result of fixing a file.
@@ -31,6 +54,10 @@ type Result struct {
 	// Violations is the number of violations found.
 	Violations int
 
+	// Report holds the violations found and the expected method order of the
+	// offending structs. It is nil if the file could not be analyzed.
+	Report *detector.Report
+
 	// Fixed indicates if the file was fixed.
 	Fixed bool
 
@@ -40,23 +67,68 @@ type Result struct {
 	// FixedContent is the fixed file content.
 	FixedContent []byte
 
+	// Replacements turn OriginalContent into FixedContent, sorted by offset.
+	// Replacements made by reordering the methods of a single struct carry
+	// its name.
+	Replacements []Replacement
+
 	// Error is any error that occurred during processing.
 	Error error
 }
 
+// SetConfigLoader makes the fixer process the files of each directory with
+// the configuration loader resolves for it instead of its own.
+func (f *Fixer) SetConfigLoader(loader *config.Loader) {
+	f.configs = loader
+}
+
+// GeneratedSkipped returns the number of generated files that directory walks
+// have skipped so far.
+func (f *Fixer) GeneratedSkipped() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.generated
+}
+
 // ProcessFile processes a single file for funcorder violations.
 func (f *Fixer) ProcessFile(filePath string) *Result {
-	result := &Result{
-		FilePath: filePath,
-	}
-
 	// Read the file
 	src, err := os.ReadFile(filePath)
 	if err != nil {
-		result.Error = fmt.Errorf("failed to read file: %w", err)
+		return &Result{
+			FilePath: filePath,
+			Error:    fmt.Errorf("failed to read file: %w", err),
+		}
+	}
+	return f.ProcessSource(filePath, src)
+}
+
+// ProcessSource processes src, the content of the file at filePath, for
+// funcorder violations. The file need not exist on disk: filePath is used in
+// messages and to resolve the configuration of its directory, so that editor
+// buffers and other unsaved content can be processed.
+func (f *Fixer) ProcessSource(filePath string, src []byte) *Result {
+	result := &Result{
+		FilePath:        filePath,
+		OriginalContent: src,
+	}
+
+	if f.configs != nil {
+		sub, err := f.fixerFor(filepath.Dir(filePath))
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		return sub.ProcessSource(filePath, src)
+	}
+
+	key, cached := f.cacheKey([]string{filePath}, [][]byte{src})
+	if cached && f.loadCached(key, []*Result{result}) {
 		return result
 	}
-	result.OriginalContent = src
+	if cached {
+		defer f.storeCached(key, []*Result{result})
+	}
 
 	// Parse the file
 	fset := token.NewFileSet()
@@ -67,8 +139,9 @@ func (f *Fixer) ProcessFile(filePath string) *Result {
 	}
 
 	// Detect violations
-	det := detector.NewDetector(fset, f.config)
+	det := f.newDetector(fset, filePath, file)
 	report := det.Detect(file, filePath)
+	result.Report = report
 	result.Violations = len(report.Violations)
 
 	// If no violations or not in fix mode, return
@@ -77,23 +150,55 @@ func (f *Fixer) ProcessFile(filePath string) *Result {
 	}
 
 	// Fix the file
-	fixedContent, err := f.fixFile(fset, file, src, report)
+	fixedContent, replacements, err := f.fixFile(fset, file, src, report)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to fix file: %w", err)
 		return result
 	}
 
 	result.FixedContent = fixedContent
+	result.Replacements = replacements
 	result.Fixed = true
 
 	return result
 }
 
-// ProcessDirectory processes all Go files in a directory.
+// ProcessDirectory processes all Go files in a directory and returns their
+// results. See ProcessDirectoryFunc.
 func (f *Fixer) ProcessDirectory(dirPath string) []*Result {
 	var results []*Result
+	f.ProcessDirectoryFunc(dirPath, func(result *Result) {
+		results = append(results, result)
+	})
+	return results
+}
 
-	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+// ProcessDirectoryFunc processes all Go files in a directory and calls fn
+// with each result as soon as it is available, sorted by path, so that the
+// results need not be held in memory together. Files are filtered by the
+// Exclude and Include patterns and, with GitIgnore, by .gitignore files.
+// Generated files are skipped unless IncludeGenerated is set. Up to Jobs
+// files are processed concurrently.
+func (f *Fixer) ProcessDirectoryFunc(dirPath string, fn func(*Result)) {
+	var paths []string
+	err := walkGoFiles(dirPath, f.skip, func(path string) {
+		paths = append(paths, path)
+	})
+
+	f.processFilesFunc(paths, fn)
+	if err != nil {
+		fn(&Result{
+			FilePath: dirPath,
+			Error:    fmt.Errorf("failed to walk directory: %w", err),
+		})
+	}
+}
+
+// walkGoFiles calls fn for every .go file under dirPath, skipping vendor and
+// hidden directories as well as files and directories for which skip
+// returns true.
+func walkGoFiles(dirPath string, skip func(path string, isDir bool) bool, fn func(path string)) error {
+	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -104,44 +209,36 @@ func (f *Fixer) ProcessDirectory(dirPath string) []*Result {
 			if name == "vendor" || (name != "." && name != ".." && len(name) > 0 && name[0] == '.') {
 				return filepath.SkipDir
 			}
+			if path != dirPath && skip(path, true) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
 		// Only process .go files
-		if filepath.Ext(path) != ".go" {
+		if filepath.Ext(path) != ".go" || skip(path, false) {
 			return nil
 		}
 
-		result := f.ProcessFile(path)
-		results = append(results, result)
+		fn(path)
 		return nil
 	})
-
-	if err != nil {
-		results = append(results, &Result{
-			FilePath: dirPath,
-			Error:    fmt.Errorf("failed to walk directory: %w", err),
-		})
-	}
-
-	return results
 }
 
-// WriteResult writes the fixed content to the file or displays a diff.
+// WriteResult writes the fixed content to the file or displays a diff. Files
+// are replaced atomically and keep their mode; ErrModified is returned
+// instead if the file changed since it was read.
 func (f *Fixer) WriteResult(result *Result) error {
 	if !result.Fixed || len(result.FixedContent) == 0 {
 		return nil
 	}
 
 	if f.config.Write {
-		return os.WriteFile(result.FilePath, result.FixedContent, 0644)
+		return writeFileAtomic(result.FilePath, result.FixedContent, result.OriginalContent)
 	}
 
 	if f.config.Diff {
-		// Print diff
-		fmt.Printf("--- %s\n", result.FilePath)
-		fmt.Printf("+++ %s (fixed)\n", result.FilePath)
-		fmt.Println(string(result.FixedContent))
+		fmt.Print(FormatDiff(result.FilePath, result.OriginalContent, result.FixedContent))
 		return nil
 	}
 
@@ -150,10 +247,93 @@ func (f *Fixer) WriteResult(result *Result) error {
 	return nil
 }
 
-// fixFile applies fixes to a file and returns the fixed content.
-func (f *Fixer) fixFile(fset *token.FileSet, file *ast.File, src []byte, report *detector.Report) ([]byte, error) {
+// fixerFor returns the fixer for the files in dir: f itself, or a fixer for
+// the configuration of dir when a config loader is set.
+func (f *Fixer) fixerFor(dir string) (*Fixer, error) {
+	if f.configs == nil {
+		return f, nil
+	}
+	resolved, err := f.configs.Load(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sub, ok := f.fixers[resolved.Config]
+	if !ok {
+		sub = NewFixer(resolved.Config)
+		sub.cache = f.cache
+		f.fixers[resolved.Config] = sub
+	}
+	return sub, nil
+}
+
+// skip reports whether a directory walk should skip path, using the
+// configuration of its directory: path matches an exclude pattern or is
+// ignored by git, or it is a file that matches no include pattern or, unless
+// generated files are included, a generated file.
+func (f *Fixer) skip(path string, isDir bool) bool {
+	sub, err := f.fixerFor(filepath.Dir(path))
+	if err != nil {
+		// The error is reported when the files are processed.
+		return false
+	}
+	cfg := sub.config
+	if cfg.Excluded(path) || (cfg.GitIgnore && f.gitignored(path, isDir)) {
+		return true
+	}
+	if isDir {
+		return false
+	}
+	if !cfg.Included(path) {
+		return true
+	}
+	if !cfg.IncludeGenerated && isGenerated(path) {
+		f.mu.Lock()
+		f.generated++
+		f.mu.Unlock()
+		return true
+	}
+	return false
+}
+
+// fixFile applies fixes to a file and returns the fixed content together with
+// the replacements that turn src into it. The fix is verified before it is
+// returned.
+func (f *Fixer) fixFile(fset *token.FileSet, file *ast.File, src []byte, report *detector.Report) ([]byte, []Replacement, error) {
+	fixed, replacements, err := f.reorderFile(fset, report.FilePath, file, src)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := f.verifyFix([]string{report.FilePath}, [][]byte{src}, [][]byte{fixed}); err != nil {
+		return nil, nil, err
+	}
+	return fixed, replacements, nil
+}
+
+// reorderFile places the constructors and reorders the methods of the parsed
+// file at path, without verifying the result.
+func (f *Fixer) reorderFile(fset *token.FileSet, path string, file *ast.File, src []byte) ([]byte, []Replacement, error) {
+	original := src
+
+	// Moving constructors shifts every offset, so the methods are reordered
+	// on a fresh parse of the result.
+	if f.config.StandaloneConstructors {
+		placed := f.placeConstructors(fset, path, file, src)
+		if !bytes.Equal(placed, src) {
+			fset = token.NewFileSet()
+			reparsed, err := parser.ParseFile(fset, path, placed, parser.ParseComments|parser.AllErrors)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse after moving constructors: %w", err)
+			}
+			file, src = reparsed, placed
+		}
+	}
+
 	// Collect structs that need reordering
-	det := detector.NewDetector(fset, f.config)
+	det := f.newDetector(fset, path, file)
 	structs := det.CollectStructMethods(file)
 
 	// Filter to only structs that need reordering
@@ -164,31 +344,51 @@ func (f *Fixer) fixFile(fset *token.FileSet, file *ast.File, src []byte, report
 		}
 	}
 
-	if len(needsReorder) == 0 {
-		return src, nil
-	}
-
 	// Reorder the methods
 	reorderer := NewReorderer(fset)
-	return reorderer.ReorderStructMethods(file, src, needsReorder)
-}
+	replacements, err := reorderer.BuildReplacements(file, src, needsReorder)
+	if err != nil {
+		return nil, nil, err
+	}
+	fixed := ApplyReplacements(src, replacements)
 
-// FormatDiff generates a unified diff between original and fixed content.
-func FormatDiff(filePath string, original, fixed []byte) string {
-	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprintf("--- a/%s\n", filePath))
-	buf.WriteString(fmt.Sprintf("+++ b/%s\n", filePath))
+	// The reorder replacements are relative to the content with the
+	// constructors already moved; describe the whole change instead.
+	if !bytes.Equal(src, original) {
+		replacements = diffReplacements(original, fixed)
+	}
+	return fixed, replacements, nil
+}
 
-	// Simple line-by-line diff
-	origLines := bytes.Split(original, []byte("\n"))
-	fixedLines := bytes.Split(fixed, []byte("\n"))
+// isGenerated reports whether the Go file at path carries the standard
+// "// Code generated ... DO NOT EDIT." comment. Only the comments up to the
+// package clause are parsed.
+func isGenerated(path string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly|parser.ParseComments)
+	return err == nil && ast.IsGenerated(file)
+}
 
-	_ = origLines  // Used in future diff implementation
-	_ = fixedLines // Used in future diff implementation
+// diffReplacements returns a single replacement turning original into fixed,
+// covering the bytes between their common prefix and suffix. It returns nil
+// when both are equal.
+func diffReplacements(original, fixed []byte) []Replacement {
+	if bytes.Equal(original, fixed) {
+		return nil
+	}
 
-	buf.WriteString(fmt.Sprintf("@@ -1,%d +1,%d @@\n", len(origLines), len(fixedLines)))
-	// Note: This is a simplified diff - for production use, consider
-	// using a proper diff library
+	prefix := 0
+	for prefix < len(original) && prefix < len(fixed) && original[prefix] == fixed[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(original)-prefix && suffix < len(fixed)-prefix &&
+		original[len(original)-1-suffix] == fixed[len(fixed)-1-suffix] {
+		suffix++
+	}
 
-	return buf.String()
+	return []Replacement{{
+		Start: prefix,
+		End:   len(original) - suffix,
+		Text:  string(fixed[prefix : len(fixed)-suffix]),
+	}}
 }
@@ -2,15 +2,18 @@ package fixer
 
 import (
 	"bytes"
+	"errors"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/detector"
 )
 
 // --- spliceBytes tests ---
@@ -78,6 +81,72 @@ func TestSpliceBytes_PreservesSuffix(t *testing.T) {
 	}
 }
 
+// --- Replacement tests ---
+
+func TestApplyReplacements_UnorderedInput(t *testing.T) {
+	src := []byte("aaa bbb ccc")
+	reps := []Replacement{
+		{Start: 0, End: 3, Text: "ccc"},
+		{Start: 8, End: 11, Text: "aaa"},
+	}
+	result := ApplyReplacements(src, reps)
+	if string(result) != "ccc bbb aaa" {
+		t.Errorf("got %q, want %q", result, "ccc bbb aaa")
+	}
+	if string(src) != "aaa bbb ccc" {
+		t.Errorf("source was modified: %q", src)
+	}
+}
+
+func TestBuildReplacements_SkipsUnchangedSlots(t *testing.T) {
+	src := `package foo
+
+type Svc struct{}
+
+func (s *Svc) NewSvc() *Svc { return s }
+
+func (s *Svc) b() {}
+
+func (s *Svc) Run() {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	det := detector.NewDetector(fset, config.DefaultConfig())
+	reps, err := NewReorderer(fset).BuildReplacements(file, []byte(src), det.CollectStructMethods(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Expected order NewSvc, Run, b: the constructor keeps its slot.
+	if len(reps) != 2 {
+		t.Fatalf("expected 2 replacements, got %d", len(reps))
+	}
+	kept := "func (s *Svc) NewSvc() *Svc { return s }"
+	keptStart := strings.Index(src, kept)
+	keptEnd := keptStart + len(kept)
+	for i, rep := range reps {
+		if rep.StructName != "Svc" {
+			t.Errorf("reps[%d].StructName = %q, want %q", i, rep.StructName, "Svc")
+		}
+		if i > 0 && reps[i-1].Start >= rep.Start {
+			t.Errorf("replacements not sorted by start offset: %d >= %d", reps[i-1].Start, rep.Start)
+		}
+		if rep.Start < keptEnd && rep.End > keptStart {
+			t.Errorf("reps[%d] [%d, %d) covers the unchanged constructor slot [%d, %d)", i, rep.Start, rep.End, keptStart, keptEnd)
+		}
+	}
+	if reps[0].Text != "func (s *Svc) Run() {}" {
+		t.Errorf("first slot text = %q, want Run", reps[0].Text)
+	}
+	if fixed := string(ApplyReplacements([]byte(src), reps)); !strings.Contains(fixed, kept+"\n\nfunc (s *Svc) Run() {}\n\nfunc (s *Svc) b() {}") {
+		t.Errorf("unexpected result:\n%s", fixed)
+	}
+}
+
 // --- GetMethodBlock tests ---
 
 func TestGetMethodBlock_WithDocComment(t *testing.T) {
@@ -254,6 +323,86 @@ func TestWriteResult_WriteMode(t *testing.T) {
 	}
 }
 
+func TestWriteResult_KeepsMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "script.go")
+	if err := os.WriteFile(path, []byte("original"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0o775|os.ModeSetgid); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.go")
+	if err := os.Symlink("script.go", link); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Write = true
+	f := NewFixer(cfg)
+
+	result := &Result{
+		FilePath:        link,
+		Fixed:           true,
+		OriginalContent: []byte("original"),
+		FixedContent:    []byte("fixed content"),
+	}
+	if err := f.WriteResult(result); err != nil {
+		t.Fatalf("WriteResult returned error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 0o775 | os.ModeSetgid; info.Mode() != want {
+		t.Errorf("mode = %v, want %v", info.Mode(), want)
+	}
+	if written, _ := os.ReadFile(path); string(written) != "fixed content" {
+		t.Errorf("written = %q, want %q", written, "fixed content")
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected %s to stay a symbolic link, got %v, %v", link, info, err)
+	}
+
+	// No temporary files are left behind.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected only the file and the link, got %v", entries)
+	}
+}
+
+func TestWriteResult_Modified(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.go")
+
+	cfg := config.DefaultConfig()
+	cfg.Write = true
+	f := NewFixer(cfg)
+
+	// A change of the same size is caught by comparing the content.
+	for _, current := range []string{"edited by hand", "ORIGINAL"} {
+		if err := os.WriteFile(path, []byte(current), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		result := &Result{
+			FilePath:        path,
+			Fixed:           true,
+			OriginalContent: []byte("original"),
+			FixedContent:    []byte("fixed content"),
+		}
+		if err := f.WriteResult(result); !errors.Is(err, ErrModified) {
+			t.Errorf("%q: expected ErrModified, got %v", current, err)
+		}
+		if written, _ := os.ReadFile(path); string(written) != current {
+			t.Errorf("%q: file was overwritten with %q", current, written)
+		}
+	}
+}
+
 // --- ProcessDirectory tests ---
 
 func TestProcessDirectory_EmptyDir(t *testing.T) {
@@ -458,3 +607,59 @@ func TestProcessDirectory_ProcessesGoFiles(t *testing.T) {
 		t.Errorf("expected result for %s, got %s", goFile, results[0].FilePath)
 	}
 }
+
+// --- diffLines tests ---
+
+func TestDiffLines_Minimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 200; iter++ {
+		a := make([]string, rng.Intn(12))
+		b := make([]string, rng.Intn(12))
+		for i := range a {
+			a[i] = string(rune('a' + rng.Intn(3)))
+		}
+		for i := range b {
+			b[i] = string(rune('a' + rng.Intn(3)))
+		}
+
+		var gotA, gotB []string
+		edits := 0
+		for _, op := range diffLines(a, b) {
+			if op.kind != '+' {
+				gotA = append(gotA, op.line)
+			}
+			if op.kind != '-' {
+				gotB = append(gotB, op.line)
+			}
+			if op.kind != ' ' {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("diff of %q and %q does not reproduce them", a, b)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); edits != want {
+			t.Fatalf("diff of %q and %q has %d edits, want %d", a, b, edits, want)
+		}
+	}
+}
+
+// lcsLength returns the length of the longest common subsequence of a and b.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
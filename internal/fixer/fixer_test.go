@@ -1,11 +1,15 @@
 package fixer_test
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/vajrock/funcorder-fix/internal/cache"
 	"github.com/vajrock/funcorder-fix/internal/config"
 	"github.com/vajrock/funcorder-fix/internal/fixer"
 )
@@ -118,6 +122,91 @@ func TestProcessFile_MixedViolations(t *testing.T) {
 	}
 }
 
+func TestProcessFile_Alphabetical(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	cfg.Alphabetical = true
+
+	f := fixer.NewFixer(cfg)
+	result := f.ProcessFile(testdataPath("src", "alphabetical.go"))
+
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	// Get and Delete precede Set, evict precedes reset.
+	if result.Violations != 3 {
+		t.Errorf("expected 3 violations, got %d", result.Violations)
+	}
+
+	golden, err := os.ReadFile(testdataPath("golden", "alphabetical.go"))
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if string(result.FixedContent) != string(golden) {
+		t.Errorf("FixedContent does not match golden file.\ngot:\n%s\nwant:\n%s",
+			result.FixedContent, golden)
+	}
+
+	if again := f.ProcessFile(testdataPath("golden", "alphabetical.go")); again.Violations != 0 || again.Fixed {
+		t.Errorf("expected the golden file to be sorted, got %d violations", again.Violations)
+	}
+}
+
+func TestProcessFile_IgnoreDirectives(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+
+	f := fixer.NewFixer(cfg)
+	result := f.ProcessFile(testdataPath("src", "ignore.go"))
+
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	// Only listen is reported: init and Pipeline are suppressed.
+	if result.Violations != 1 {
+		t.Errorf("expected 1 violation, got %d", result.Violations)
+	}
+
+	golden, err := os.ReadFile(testdataPath("golden", "ignore.go"))
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if string(result.FixedContent) != string(golden) {
+		t.Errorf("FixedContent does not match golden file.\ngot:\n%s\nwant:\n%s",
+			result.FixedContent, golden)
+	}
+
+	cfg.NoIgnore = true
+	if audit := fixer.NewFixer(cfg).ProcessFile(testdataPath("src", "ignore.go")); audit.Violations != 3 {
+		t.Errorf("expected 3 violations with NoIgnore, got %d", audit.Violations)
+	}
+}
+
+func TestProcessSource(t *testing.T) {
+	src, err := os.ReadFile(testdataPath("src", "exported_only.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := os.ReadFile(testdataPath("golden", "exported_only.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	path := filepath.Join(t.TempDir(), "unsaved.go")
+	result := fixer.NewFixer(cfg).ProcessSource(path, src)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if !result.Fixed || string(result.FixedContent) != string(golden) {
+		t.Errorf("expected the fixed source, got:\n%s", result.FixedContent)
+	}
+	if result.Report.Violations[0].Position.Filename != path {
+		t.Errorf("expected violations in %s, got %v", path, result.Report.Violations[0])
+	}
+}
+
 func TestProcessFile_NoFixMode(t *testing.T) {
 	cfg := config.DefaultConfig()
 	// Fix defaults to false in DefaultConfig
@@ -137,6 +226,9 @@ func TestProcessFile_NoFixMode(t *testing.T) {
 	if result.FixedContent != nil {
 		t.Errorf("expected FixedContent==nil, got %d bytes", len(result.FixedContent))
 	}
+	if result.Report == nil || len(result.Report.Violations) != result.Violations {
+		t.Errorf("expected Report with %d violations, got %v", result.Violations, result.Report)
+	}
 }
 
 func TestProcessFile_NoConstructorCheck(t *testing.T) {
@@ -159,6 +251,42 @@ func TestProcessFile_NoConstructorCheck(t *testing.T) {
 	}
 }
 
+func TestProcessSource_NoConstructorCheckKeepsGroups(t *testing.T) {
+	const src = `package p
+
+type S struct{}
+
+func (s *S) helper() {}
+
+func (s *S) Run() {}
+
+func (s *S) Order() {}
+`
+	const want = `package p
+
+type S struct{}
+
+func (s *S) Run() {}
+
+func (s *S) Order() {}
+
+func (s *S) helper() {}
+`
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	cfg.CheckConstructor = false
+
+	// Order matches the Or prefix, but without the constructor rule it is
+	// an exported method like Run and keeps its place after it.
+	result := fixer.NewFixer(cfg).ProcessSource("p.go", []byte(src))
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if result.Violations != 1 || string(result.FixedContent) != want {
+		t.Errorf("got %d violations and\n%s\nwant 1 and\n%s", result.Violations, result.FixedContent, want)
+	}
+}
+
 func TestProcessFile_NoExportedCheck(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Fix = true
@@ -298,3 +426,274 @@ func TestProcessFile_MultiStruct_Idempotent(t *testing.T) {
 		t.Error("expected Fixed==false on golden file")
 	}
 }
+
+func TestProcessFile_Cache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	src := "package p\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheDir := filepath.Join(dir, "cache")
+	c := cache.New(cacheDir, "test")
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+
+	f := fixer.NewFixer(cfg)
+	f.SetCache(c)
+	first := f.ProcessFile(path)
+	if first.Error != nil || !first.Fixed {
+		t.Fatalf("expected the file to be fixed, got %+v", first)
+	}
+	entries := cacheEntries(t, cacheDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 cache entry, got %d", len(entries))
+	}
+
+	// Mark the entry, so that a hit can be told from a fresh run.
+	data, err := os.ReadFile(entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.Replace(data, []byte(`"Message":"`), []byte(`"Message":"cached `), 1)
+	if err := os.WriteFile(entries[0], data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f = fixer.NewFixer(cfg)
+	f.SetCache(c)
+	second := f.ProcessFile(path)
+	if second.Violations != first.Violations || !strings.HasPrefix(second.Report.Violations[0].Message, "cached ") {
+		t.Errorf("expected a cache hit, got %+v", second.Report.Violations[0])
+	}
+	if !second.Fixed || string(second.FixedContent) != string(first.FixedContent) || string(second.OriginalContent) != src {
+		t.Errorf("expected the cached fix, got %+v", second)
+	}
+
+	// Output settings share entries; other settings and content do not.
+	cfg.Write = true
+	f = fixer.NewFixer(cfg)
+	f.SetCache(c)
+	f.ProcessFile(path)
+	cfg.CheckConstructor = false
+	f = fixer.NewFixer(cfg)
+	f.SetCache(c)
+	f.ProcessFile(path)
+	if err := os.WriteFile(path, []byte(src+"\nfunc (s *S) C() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f.ProcessFile(path)
+	if n := len(cacheEntries(t, cacheDir)); n != 3 {
+		t.Errorf("expected 3 cache entries, got %d", n)
+	}
+}
+
+// cacheEntries returns the files of the cache in dir.
+func cacheEntries(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestProcessDirectory_Generated(t *testing.T) {
+	const src = "package p\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go":          src,
+		"mock.go":       "// Code generated by mockgen. DO NOT EDIT.\n\n" + src,
+		"api/api.pb.go": "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: api.proto\n\n" + src,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	f := fixer.NewFixer(cfg)
+	results := f.ProcessDirectory(dir)
+	if len(results) != 1 || filepath.Base(results[0].FilePath) != "a.go" {
+		t.Errorf("expected only a.go to be processed, got %v", results)
+	}
+	if n := f.GeneratedSkipped(); n != 2 {
+		t.Errorf("expected 2 generated files to be skipped, got %d", n)
+	}
+
+	// Files named explicitly are always processed.
+	if r := f.ProcessFile(filepath.Join(dir, "mock.go")); r.Violations != 1 {
+		t.Errorf("expected mock.go to be checked when named, got %+v", r)
+	}
+
+	cfg.IncludeGenerated = true
+	f = fixer.NewFixer(cfg)
+	if results := f.ProcessDirectory(dir); len(results) != 3 || f.GeneratedSkipped() != 0 {
+		t.Errorf("expected all 3 files with IncludeGenerated, got %d results, %d skipped", len(results), f.GeneratedSkipped())
+	}
+}
+
+func TestProcessDirectory_GitIgnore(t *testing.T) {
+	const src = "package p\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	root := t.TempDir()
+	files := map[string]string{
+		".git/HEAD":          "ref: refs/heads/main\n",
+		".gitignore":         "# build output\n/build/\n*_mock.go\n!keep_mock.go\n",
+		"a.go":               src,
+		"a_mock.go":          src,
+		"keep_mock.go":       src,
+		"build/out.go":       src,
+		"pkg/build/in.go":    src,
+		"pkg/.gitignore":     "gen/**\nlocal.go\n",
+		"pkg/gen/x/y.go":     src,
+		"pkg/local.go":       src,
+		"pkg/b.go":           src,
+		"third_party/lib.go": src,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	processed := func(cfg *config.Config, dir string) []string {
+		var paths []string
+		for _, result := range fixer.NewFixer(cfg).ProcessDirectory(dir) {
+			rel, _ := filepath.Rel(root, result.FilePath)
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.GitIgnore = true
+	want := "a.go keep_mock.go pkg/b.go pkg/build/in.go third_party/lib.go"
+	if got := strings.Join(processed(cfg, root), " "); got != want {
+		t.Errorf("processed %q, want %q", got, want)
+	}
+
+	// The .gitignore files of parent directories apply as well.
+	if got := strings.Join(processed(cfg, filepath.Join(root, "pkg")), " "); got != "pkg/b.go pkg/build/in.go" {
+		t.Errorf("processed %q in pkg", got)
+	}
+
+	cfg = config.DefaultConfig()
+	cfg.Exclude = []string{"third_party", "build"}
+	cfg.Include = []string{"*_mock.go", filepath.Join(root, "pkg", "*")}
+	want = "a_mock.go keep_mock.go pkg/b.go pkg/gen/x/y.go pkg/local.go"
+	if got := strings.Join(processed(cfg, root), " "); got != want {
+		t.Errorf("processed %q, want %q", got, want)
+	}
+}
+
+func TestProcessDirectoryFunc_Jobs(t *testing.T) {
+	const src = "package p\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	var want []string
+	for i := 0; i < 40; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("d%d", i%3), fmt.Sprintf("f%02d.go", i))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, name)
+	}
+	sort.Strings(want)
+
+	for _, jobs := range []int{1, 4, 0} {
+		cfg := config.DefaultConfig()
+		cfg.Fix = true
+		cfg.Jobs = jobs
+
+		var got []string
+		fixer.NewFixer(cfg).ProcessDirectoryFunc(dir, func(result *fixer.Result) {
+			if result.Error != nil || !result.Fixed {
+				t.Errorf("jobs=%d: %s not fixed: %v", jobs, result.FilePath, result.Error)
+			}
+			got = append(got, result.FilePath)
+		})
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("jobs=%d: results not sorted by path:\n%s", jobs, strings.Join(got, "\n"))
+		}
+
+		// In package mode the results of a package stay together.
+		cfg.Package = true
+		got = got[:0]
+		fixer.NewFixer(cfg).ProcessDirectoryFunc(dir, func(result *fixer.Result) {
+			got = append(got, result.FilePath)
+		})
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("jobs=%d: package results out of order:\n%s", jobs, strings.Join(got, "\n"))
+		}
+	}
+}
+
+func TestProcessDirectory_ConfigFiles(t *testing.T) {
+	const src = "package p\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	files := map[string]string{
+		config.FileName:                 "rules:\n  exported: false\nexclude: [\"*_gen.go\"]\n",
+		"a.go":                          src,
+		"strict/" + config.FileName:     "rules:\n  exported: true\nexclude: [skipped/*]\n",
+		"strict/a.go":                   src,
+		"strict/a_gen.go":               src,
+		"strict/skipped/a.go":           src,
+		"strict/pkg/" + config.FileName: "package: true\n",
+		"strict/pkg/a.go":               src,
+		"broken/" + config.FileName:     "rules: [\n",
+		"broken/a.go":                   src,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f := fixer.NewFixer(config.DefaultConfig())
+	f.SetConfigLoader(config.NewLoader(config.DefaultConfig(), nil))
+
+	got := make(map[string]*fixer.Result)
+	for _, result := range f.ProcessDirectory(dir) {
+		rel, _ := filepath.Rel(dir, result.FilePath)
+		got[filepath.ToSlash(rel)] = result
+	}
+
+	if len(got) != 4 {
+		t.Errorf("expected 4 results, got %v", got)
+	}
+	if r := got["a.go"]; r == nil || r.Violations != 0 {
+		t.Errorf("a.go: expected the exported rule to be disabled, got %+v", r)
+	}
+	if r := got["strict/a.go"]; r == nil || r.Violations != 1 {
+		t.Errorf("strict/a.go: expected the nested file to enable the exported rule, got %+v", r)
+	}
+	if r := got["strict/pkg/a.go"]; r == nil || r.Violations != 1 {
+		t.Errorf("strict/pkg/a.go: expected 1 violation in package mode, got %+v", r)
+	}
+	if r := got["broken/a.go"]; r == nil || r.Error == nil || !strings.Contains(r.Error.Error(), "failed to load config") {
+		t.Errorf("broken/a.go: expected a config error, got %+v", r)
+	}
+}
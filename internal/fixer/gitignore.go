@@ -0,0 +1,156 @@
+package fixer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// gitignoreFile is the name of the files listing paths git ignores.
+const gitignoreFile = ".gitignore"
+
+// gitignoreRule is a single pattern of a .gitignore file.
+type gitignoreRule struct {
+	// base is the directory of the .gitignore file.
+	base string
+
+	// segments is the pattern split at slashes. Patterns that are not
+	// anchored to base start with "**".
+	segments []string
+
+	// negate re-includes paths matched by an earlier rule (!pattern).
+	negate bool
+
+	// dirOnly matches directories only (pattern/).
+	dirOnly bool
+}
+
+// parseGitignore parses the content of the .gitignore file in dir. Blank
+// lines, comments and patterns that are not valid globs are skipped.
+func parseGitignore(dir string, data []byte) []gitignoreRule {
+	var rules []gitignoreRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		rule := gitignoreRule{base: dir}
+		if line[0] == '!' {
+			rule.negate = true
+			line = line[1:]
+		} else if line[0] == '\\' {
+			// \# and \! escape a leading # or !.
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// A slash at the start or in the middle anchors the pattern to
+		// the directory of the .gitignore file.
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if !anchored && line != "**" {
+			line = "**/" + line
+		}
+
+		valid := true
+		for _, seg := range strings.Split(line, "/") {
+			if _, err := path.Match(seg, ""); err != nil {
+				valid = false
+			}
+		}
+		if valid {
+			rule.segments = strings.Split(line, "/")
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// match reports whether the rule matches the absolute path.
+func (r gitignoreRule) match(abs string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	rel, err := filepath.Rel(r.base, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	return matchSegments(r.segments, strings.Split(filepath.ToSlash(rel), "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches any number of segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && matchSegments(pattern[1:], segments[1:])
+}
+
+// gitignored reports whether path is ignored by the .gitignore files of its
+// parent directories. The last matching rule wins, and rules of nested
+// files come after those of their parents.
+func (f *Fixer) gitignored(path string, isDir bool) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	ignored := false
+	for _, rule := range f.gitignoreRules(filepath.Dir(abs)) {
+		if rule.match(abs, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// gitignoreRules returns the rules of the .gitignore files in dir and its
+// parents up to the root of the git repository, outermost first. Results
+// are cached per directory.
+func (f *Fixer) gitignoreRules(dir string) []gitignoreRule {
+	f.mu.Lock()
+	rules, ok := f.gitignores[dir]
+	f.mu.Unlock()
+	if ok {
+		return rules
+	}
+
+	// The repository root, marked by .git, ends the search.
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if parent := filepath.Dir(dir); parent != dir {
+			rules = append(rules, f.gitignoreRules(parent)...)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, gitignoreFile)); err == nil {
+		rules = append(rules, parseGitignore(dir, data)...)
+	}
+
+	f.mu.Lock()
+	f.gitignores[dir] = rules
+	f.mu.Unlock()
+	return rules
+}
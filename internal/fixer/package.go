@@ -0,0 +1,335 @@
+package fixer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/vajrock/funcorder-fix/internal/detector"
+)
+
+// packageFile is a single file of a package processed in package mode.
+type packageFile struct {
+	path   string
+	src    []byte
+	file   *ast.File
+	result *Result
+}
+
+// ProcessPackage processes the given files in package mode. Files are grouped
+// into packages by directory and package name, and the methods of each struct
+// are collected across all files of its package. One Result is returned per
+// path, in the same order.
+func (f *Fixer) ProcessPackage(paths []string) []*Result {
+	results := make([]*Result, len(paths))
+	groups := make(map[string][]*packageFile)
+	var keys []string
+
+	fset := token.NewFileSet()
+	for i, path := range paths {
+		result := &Result{FilePath: path}
+		results[i] = result
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to read file: %w", err)
+			continue
+		}
+		result.OriginalContent = src
+
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments|parser.AllErrors)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to parse file: %w", err)
+			continue
+		}
+
+		key := filepath.Dir(path) + "\x00" + file.Name.Name
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], &packageFile{
+			path:   path,
+			src:    src,
+			file:   file,
+			result: result,
+		})
+	}
+
+	for _, key := range keys {
+		sub, err := f.fixerFor(filepath.Dir(groups[key][0].path))
+		if err != nil {
+			for _, pf := range groups[key] {
+				pf.result.Error = err
+			}
+			continue
+		}
+		if err := sub.processPackageFiles(fset, groups[key]); err != nil {
+			for _, pf := range groups[key] {
+				pf.result.Error = fmt.Errorf("failed to fix package: %w", err)
+			}
+		}
+	}
+
+	return results
+}
+
+// processPackageFiles detects and fixes violations in the files of a single
+// package. The results are cached together, keyed by all files.
+func (f *Fixer) processPackageFiles(fset *token.FileSet, pfs []*packageFile) error {
+	files := make([]*ast.File, len(pfs))
+	paths := make([]string, len(pfs))
+	srcs := make([][]byte, len(pfs))
+	results := make([]*Result, len(pfs))
+	for i, pf := range pfs {
+		files[i] = pf.file
+		paths[i] = pf.path
+		srcs[i] = pf.src
+		results[i] = pf.result
+	}
+
+	key, cached := f.cacheKey(paths, srcs)
+	if cached && f.loadCached(key, results) {
+		return nil
+	}
+	err := f.fixPackageFiles(fset, pfs, files, paths)
+	if cached && err == nil {
+		f.storeCached(key, results)
+	}
+	return err
+}
+
+// fixPackageFiles detects and fixes violations in the parsed files of a
+// single package.
+func (f *Fixer) fixPackageFiles(fset *token.FileSet, pfs []*packageFile, files []*ast.File, paths []string) error {
+	det := f.newDetector(fset, paths[0], files[0])
+	reports := det.DetectPackage(files, paths)
+
+	hasViolations := false
+	for i, report := range reports {
+		pfs[i].result.Report = report
+		pfs[i].result.Violations = len(report.Violations)
+		if report.HasViolations() {
+			hasViolations = true
+		}
+	}
+	if !hasViolations || !f.config.Fix {
+		return nil
+	}
+
+	srcs := make([][]byte, len(pfs))
+	for i, pf := range pfs {
+		srcs[i] = pf.src
+	}
+
+	fixed, err := f.reorderPackageFiles(fset, det, files, paths, srcs)
+	if err != nil {
+		return err
+	}
+	if err := f.verifyFix(paths, srcs, fixed); err != nil {
+		return err
+	}
+
+	for i, pf := range pfs {
+		if !bytes.Equal(fixed[i], pf.src) {
+			pf.result.FixedContent = fixed[i]
+			pf.result.Replacements = diffReplacements(pf.src, fixed[i])
+			pf.result.Fixed = true
+		}
+	}
+	return nil
+}
+
+// reorderPackageFiles moves, places and reorders the methods of the parsed
+// files of a package, without verifying the result.
+func (f *Fixer) reorderPackageFiles(fset *token.FileSet, det *detector.Detector, files []*ast.File, paths []string, contents [][]byte) ([][]byte, error) {
+	if f.config.MoveMethods {
+		contents = relocateMethods(fset, det, files, contents)
+	}
+	return f.reorderPackage(paths, contents)
+}
+
+// reorderPackage parses contents as one package and reorders the methods of
+// every struct within each file, using the package-wide view of the struct.
+func (f *Fixer) reorderPackage(paths []string, contents [][]byte) ([][]byte, error) {
+	if f.config.StandaloneConstructors {
+		placed, err := f.placePackageConstructors(paths, contents)
+		if err != nil {
+			return nil, err
+		}
+		contents = placed
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, len(contents))
+	for i, src := range contents {
+		file, err := parser.ParseFile(fset, paths[i], src, parser.ParseComments|parser.AllErrors)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s after moving methods: %w", paths[i], err)
+		}
+		files[i] = file
+	}
+
+	det := f.newDetector(fset, paths[0], files[0])
+	structs := det.CollectPackageStructMethods(files)
+	reorderer := NewReorderer(fset)
+
+	fixed := make([][]byte, len(contents))
+	for i, file := range files {
+		needsReorder := make(map[string]*detector.StructMethods)
+		for name, sm := range structs {
+			if fileMethods := sm.InFile(file); fileMethods != nil && fileMethods.NeedsReordering() {
+				needsReorder[name] = fileMethods
+			}
+		}
+
+		out, err := reorderer.ReorderStructMethods(file, contents[i], needsReorder)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", paths[i], err)
+		}
+		fixed[i] = out
+	}
+	return fixed, nil
+}
+
+// placePackageConstructors moves the standalone constructors of every file
+// directly after the declaration of the struct they return.
+func (f *Fixer) placePackageConstructors(paths []string, contents [][]byte) ([][]byte, error) {
+	placed := make([][]byte, len(contents))
+	for i, src := range contents {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, paths[i], src, parser.ParseComments|parser.AllErrors)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", paths[i], err)
+		}
+		placed[i] = f.placeConstructors(fset, paths[i], file, src)
+	}
+	return placed, nil
+}
+
+// relocateMethods moves methods declared outside the file of their struct to
+// the end of the struct's methods in that file (or right after the struct
+// declaration when the file has none). It returns the new file contents.
+func relocateMethods(fset *token.FileSet, det *detector.Detector, files []*ast.File, contents [][]byte) [][]byte {
+	index := make(map[*ast.File]int, len(files))
+	for i, file := range files {
+		index[file] = i
+	}
+
+	structs := det.CollectPackageStructMethods(files)
+	misplaced := det.MisplacedMethods(structs, files)
+
+	// Group moved methods by struct, keeping their source order.
+	var names []string
+	byStruct := make(map[string][]*detector.MethodInfo)
+	for _, m := range misplaced {
+		if _, ok := byStruct[m.ReceiverType]; !ok {
+			names = append(names, m.ReceiverType)
+		}
+		byStruct[m.ReceiverType] = append(byStruct[m.ReceiverType], m)
+	}
+
+	edits := make([][]Replacement, len(files))
+	for _, name := range names {
+		sm := structs[name]
+		home := detector.FileContaining(files, sm.StructPos)
+		homeIdx := index[home]
+		anchor := insertionAnchor(fset, home, sm)
+
+		var text bytes.Buffer
+		for _, m := range byStruct[name] {
+			from := detector.FileContaining(files, m.Pos)
+			fromIdx := index[from]
+			src := contents[fromIdx]
+
+			block := NewCommentPreserver(fset, from).GetMethodBlock(m.FuncDecl, src)
+			start, end := removalRange(src, fset.Position(block.StartPos).Offset, fset.Position(block.EndPos).Offset)
+			edits[fromIdx] = append(edits[fromIdx], Replacement{
+				StructName: sm.StructName,
+				Start:      start,
+				End:        end,
+			})
+
+			text.WriteString("\n\n")
+			text.WriteString(block.RawText)
+		}
+
+		edits[homeIdx] = append(edits[homeIdx], Replacement{
+			StructName: sm.StructName,
+			Start:      anchor,
+			End:        anchor,
+			Text:       text.String(),
+		})
+	}
+
+	result := make([][]byte, len(contents))
+	for i, src := range contents {
+		if len(edits[i]) == 0 {
+			result[i] = src
+			continue
+		}
+		result[i] = ApplyReplacements(src, mergeRemovals(src, edits[i]))
+	}
+	return result
+}
+
+// mergeRemovals joins removals whose widened ranges overlap, which happens
+// when adjacent methods are moved out of the same file. A removal that
+// reaches the end of src also takes the blank lines before it.
+func mergeRemovals(src []byte, reps []Replacement) []Replacement {
+	sort.Slice(reps, func(i, j int) bool {
+		return reps[i].Start < reps[j].Start
+	})
+
+	merged := reps[:0]
+	for _, rep := range reps {
+		if n := len(merged); n > 0 && merged[n-1].Text == "" && rep.Text == "" && rep.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, rep.End)
+			continue
+		}
+		merged = append(merged, rep)
+	}
+
+	if n := len(merged); n > 0 && merged[n-1].Text == "" && merged[n-1].End == len(src) {
+		last := &merged[n-1]
+		for last.Start >= 2 && src[last.Start-1] == '\n' && src[last.Start-2] == '\n' {
+			last.Start--
+		}
+	}
+	return merged
+}
+
+// insertionAnchor returns the byte offset in home after which relocated
+// methods of sm are inserted: the end of its last method in home, or the end
+// of the declaration that contains the struct type.
+func insertionAnchor(fset *token.FileSet, home *ast.File, sm *detector.StructMethods) int {
+	anchor := token.NoPos
+	for _, decl := range home.Decls {
+		if decl.Pos() <= sm.StructPos && sm.StructEnd <= decl.End() {
+			anchor = decl.End()
+			break
+		}
+	}
+	for _, m := range sm.Methods {
+		if m.Pos >= home.FileStart && m.End <= home.FileEnd && m.End > anchor {
+			anchor = m.End
+		}
+	}
+	return fset.Position(anchor).Offset
+}
+
+// removalRange widens src[start:end] so that removing it leaves no stray line
+// break or doubled blank line behind.
+func removalRange(src []byte, start, end int) (int, int) {
+	if end < len(src) && src[end] == '\n' {
+		end++
+	}
+	if bytes.HasSuffix(src[:start], []byte("\n\n")) && end < len(src) && src[end] == '\n' {
+		end++
+	}
+	return start, end
+}
@@ -0,0 +1,180 @@
+package fixer_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/cache"
+	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/fixer"
+)
+
+func splitPackagePaths() []string {
+	return []string{
+		testdataPath("src", "split_package", "service.go"),
+		testdataPath("src", "split_package", "service_helpers.go"),
+	}
+}
+
+func TestProcessPackage_CrossFileViolations(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Package = true
+
+	f := fixer.NewFixer(cfg)
+	results := f.ProcessPackage(splitPackagePaths())
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", r.FilePath, r.Error)
+		}
+		if r.Violations == 0 {
+			t.Errorf("%s: expected violations in package mode, got 0", r.FilePath)
+		}
+	}
+
+	// In single-file mode the helpers file does not declare Service, so its
+	// methods are never checked.
+	single := fixer.NewFixer(config.DefaultConfig()).ProcessFile(splitPackagePaths()[1])
+	if single.Violations != 0 {
+		t.Errorf("expected 0 violations in file mode, got %d", single.Violations)
+	}
+}
+
+func TestProcessPackage_ReorderWithinFiles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Package = true
+	cfg.Fix = true
+
+	f := fixer.NewFixer(cfg)
+	results := f.ProcessPackage(splitPackagePaths())
+
+	helpers := results[1]
+	if helpers.Error != nil {
+		t.Fatalf("unexpected error: %v", helpers.Error)
+	}
+	if !helpers.Fixed {
+		t.Fatal("expected helpers file to be fixed")
+	}
+
+	got := string(helpers.FixedContent)
+	if strings.Index(got, "Start()") > strings.Index(got, "validate()") {
+		t.Errorf("expected Start before validate in helpers file, got:\n%s", got)
+	}
+}
+
+func TestProcessPackage_MoveMethods(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Package = true
+	cfg.MoveMethods = true
+	cfg.Fix = true
+
+	f := fixer.NewFixer(cfg)
+	results := f.ProcessPackage(splitPackagePaths())
+
+	for _, r := range results {
+		if r.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", r.FilePath, r.Error)
+		}
+		if !r.Fixed {
+			t.Errorf("%s: expected Fixed==true", r.FilePath)
+			continue
+		}
+
+		golden, err := os.ReadFile(testdataPath("golden", "split_package", filepath.Base(r.FilePath)))
+		if err != nil {
+			t.Fatalf("failed to read golden file: %v", err)
+		}
+		if string(r.FixedContent) != string(golden) {
+			t.Errorf("%s: FixedContent does not match golden file.\ngot:\n%s\nwant:\n%s",
+				r.FilePath, r.FixedContent, golden)
+		}
+	}
+}
+
+func TestProcessPackage_MoveMethods_Idempotent(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Package = true
+	cfg.MoveMethods = true
+	cfg.Fix = true
+
+	f := fixer.NewFixer(cfg)
+	results := f.ProcessPackage([]string{
+		testdataPath("golden", "split_package", "service.go"),
+		testdataPath("golden", "split_package", "service_helpers.go"),
+	})
+
+	for _, r := range results {
+		if r.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", r.FilePath, r.Error)
+		}
+		if r.Violations != 0 {
+			t.Errorf("%s: expected 0 violations on golden package, got %d", r.FilePath, r.Violations)
+		}
+		if r.Fixed {
+			t.Errorf("%s: expected Fixed==false on golden package", r.FilePath)
+		}
+	}
+}
+
+func TestProcessPackage_SkipsTestFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"svc.go":      "package p\n\ntype Svc struct{}\n\nfunc (s *Svc) Run() {}\n",
+		"svc_test.go": "package p\n\nfunc (s *Svc) Helper() {}\n",
+	}
+	var paths []string
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Package = true
+	cfg.MoveMethods = true
+	cfg.Fix = true
+
+	for _, r := range fixer.NewFixer(cfg).ProcessPackage(paths) {
+		if r.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", r.FilePath, r.Error)
+		}
+		if r.Violations != 0 || r.Fixed {
+			t.Errorf("%s: methods in test files must not be moved (violations=%d, fixed=%v)",
+				r.FilePath, r.Violations, r.Fixed)
+		}
+	}
+}
+
+func TestProcessPackage_Cache(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.MoveMethods = true
+	cfg.Package = true
+	cfg.Fix = true
+
+	f := fixer.NewFixer(cfg)
+	f.SetCache(cache.New(cacheDir, "test"))
+	first := f.ProcessPackage(splitPackagePaths())
+
+	// The package is cached as a single entry.
+	if n := len(cacheEntries(t, cacheDir)); n != 1 {
+		t.Fatalf("expected 1 cache entry, got %d", n)
+	}
+
+	f = fixer.NewFixer(cfg)
+	f.SetCache(cache.New(cacheDir, "test"))
+	second := f.ProcessPackage(splitPackagePaths())
+	for i := range first {
+		if second[i].Violations != first[i].Violations || second[i].Fixed != first[i].Fixed ||
+			string(second[i].FixedContent) != string(first[i].FixedContent) {
+			t.Errorf("%s: cached result differs: %+v, want %+v", first[i].FilePath, second[i], first[i])
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package fixer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ErrNoPackages is returned by ProcessPatterns when the patterns match no
+// packages.
+var ErrNoPackages = errors.New("patterns matched no packages")
+
+// ProcessPatterns resolves Go package patterns such as ./..., ./internal/...
+// or an import path with go/packages, relative to dir or, if dir is empty,
+// the working directory, and processes the files of the matched packages,
+// including their tests. Module boundaries, go.work workspaces, build
+// constraints, the BuildTags and the GOOS and GOARCH of the environment are
+// honoured. Modules are never downloaded, so resolution works offline
+// against the module cache.
+//
+// Resolved files are filtered like the files of a directory walk and named
+// like them, relative to dir. An error is returned if the patterns cannot be
+// resolved.
+func (f *Fixer) ProcessPatterns(dir string, patterns []string) ([]*Result, error) {
+	var results []*Result
+	err := f.ProcessPatternsFunc(dir, patterns, func(result *Result) {
+		results = append(results, result)
+	})
+	return results, err
+}
+
+// ProcessPatternsFunc is like ProcessPatterns but calls fn with each result
+// as soon as it is available, sorted by path, like ProcessDirectoryFunc. fn
+// is not called if the patterns cannot be resolved.
+func (f *Fixer) ProcessPatternsFunc(dir string, patterns []string, fn func(*Result)) error {
+	files, err := f.resolvePatterns(dir, patterns)
+	if err != nil {
+		return err
+	}
+
+	var kept []string
+	for _, path := range files {
+		if !f.skipResolved(path) {
+			kept = append(kept, path)
+		}
+	}
+	f.processFilesFunc(kept, fn)
+	return nil
+}
+
+// resolvePatterns returns the Go files of the packages matching patterns,
+// relative to dir, sorted by path. Generated test mains are left out, and
+// files outside of dir keep their absolute path.
+func (f *Fixer) resolvePatterns(dir string, patterns []string) ([]string, error) {
+	cfg := f.packagesConfig(packages.NeedName | packages.NeedFiles)
+	cfg.Dir = dir
+	cfg.Tests = true
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	var listErr error
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		for _, e := range pkg.Errors {
+			if e.Kind == packages.ListError && listErr == nil {
+				listErr = fmt.Errorf("%s: %s", pkg.ID, e.Msg)
+			}
+		}
+		for _, path := range pkg.GoFiles {
+			path = relativeTo(dir, path)
+			if filepath.Ext(path) == ".go" && !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	if len(files) == 0 {
+		// A pattern outside of any module yields a package with errors only.
+		if listErr != nil {
+			return nil, listErr
+		}
+		return nil, ErrNoPackages
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// relativeTo returns path, an absolute path reported by go/packages, as it
+// would be named by a walk of dir or, if dir is empty, of the working
+// directory. It returns path unchanged if it is not below that directory.
+func relativeTo(dir, path string) string {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join(dir, rel)
+}
+
+// packagesConfig returns a go/packages configuration with mode for the build
+// tags of the fixer. GOPROXY=off keeps go list from downloading modules.
+func (f *Fixer) packagesConfig(mode packages.LoadMode) *packages.Config {
+	cfg := &packages.Config{
+		Mode: mode,
+		Env:  append(os.Environ(), "GOPROXY=off"),
+	}
+	if len(f.config.BuildTags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(f.config.BuildTags, ",")}
+	}
+	return cfg
+}
+
+// skipResolved reports whether a file resolved from a package pattern is
+// skipped by the rules of a directory walk. Exclude patterns already match
+// every path element, but a directory ignored by git is only found by
+// checking the parents of the file up to the repository root.
+func (f *Fixer) skipResolved(path string) bool {
+	if f.skip(path, false) {
+		return true
+	}
+	sub, err := f.fixerFor(filepath.Dir(path))
+	if err != nil || !sub.config.GitIgnore {
+		return false
+	}
+	for dir := filepath.Dir(path); ; {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return false
+		}
+		if f.gitignored(dir, true) {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
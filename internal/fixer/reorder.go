@@ -26,33 +26,43 @@ type structRegion struct {
 	blocks []MethodBlock // in original source order
 }
 
-// slotReplacement is a single byte-range swap: replace src[start:end] with text.
-type slotReplacement struct {
-	start int
-	end   int
-	text  string
+// Replacement is a single byte-range swap: src[Start:End] is replaced with Text.
+type Replacement struct {
+	// StructName is the struct whose method slot is rewritten.
+	StructName string
+
+	// Start is the byte offset where the slot begins.
+	Start int
+
+	// End is the byte offset where the slot ends (exclusive).
+	End int
+
+	// Text is the new content of the slot.
+	Text string
 }
 
 // ReorderStructMethods reorders methods for all structs in the file.
 // It uses per-slot byte splicing so that non-method content (standalone functions,
 // blank lines, etc.) interleaved between a struct's methods is preserved unchanged.
 func (r *Reorderer) ReorderStructMethods(file *ast.File, src []byte, structs map[string]*detector.StructMethods) ([]byte, error) {
-	// Quick check
-	needsReordering := false
-	for _, sm := range structs {
-		if sm.NeedsReordering() {
-			needsReordering = true
-			break
-		}
+	replacements, err := r.BuildReplacements(file, src, structs)
+	if err != nil {
+		return nil, err
 	}
-	if !needsReordering {
+	if len(replacements) == 0 {
 		return src, nil
 	}
+	return ApplyReplacements(src, replacements), nil
+}
 
+// BuildReplacements returns the slot replacements needed to reorder the methods
+// of every struct in structs that needs reordering. Slots whose content does not
+// change are omitted. The result is sorted by ascending start offset.
+func (r *Reorderer) BuildReplacements(file *ast.File, src []byte, structs map[string]*detector.StructMethods) ([]Replacement, error) {
 	cp := NewCommentPreserver(r.fset, file)
 
 	// Collect per-slot replacements for every struct that needs reordering.
-	var replacements []slotReplacement
+	var replacements []Replacement
 	for _, sm := range structs {
 		if !sm.NeedsReordering() {
 			continue
@@ -65,19 +75,34 @@ func (r *Reorderer) ReorderStructMethods(file *ast.File, src []byte, structs map
 		if err != nil {
 			return nil, fmt.Errorf("slot replacements for %s: %w", sm.StructName, err)
 		}
-		replacements = append(replacements, reps...)
+		for _, rep := range reps {
+			if string(src[rep.Start:rep.End]) != rep.Text {
+				replacements = append(replacements, rep)
+			}
+		}
 	}
 
-	// Process in descending start-offset order so earlier offsets stay valid.
 	sort.Slice(replacements, func(i, j int) bool {
-		return replacements[i].start > replacements[j].start
+		return replacements[i].Start < replacements[j].Start
+	})
+	return replacements, nil
+}
+
+// ApplyReplacements returns a copy of src with all replacements applied.
+// Replacements must not overlap.
+func ApplyReplacements(src []byte, replacements []Replacement) []byte {
+	ordered := append([]Replacement(nil), replacements...)
+
+	// Process in descending start-offset order so earlier offsets stay valid.
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Start > ordered[j].Start
 	})
 
 	result := append([]byte(nil), src...)
-	for _, rep := range replacements {
-		result = spliceBytes(result, rep.start, rep.end, []byte(rep.text))
+	for _, rep := range ordered {
+		result = spliceBytes(result, rep.Start, rep.End, []byte(rep.Text))
 	}
-	return result, nil
+	return result
 }
 
 // buildStructRegion builds MethodBlocks for all methods of sm (in source order).
@@ -98,10 +123,10 @@ func (r *Reorderer) buildStructRegion(cp *CommentPreserver, sm *detector.StructM
 	}, nil
 }
 
-// buildSlotReplacements returns one slotReplacement per method.
+// buildSlotReplacements returns one Replacement per method.
 // Slot i (the byte range of the i-th method in source order) receives the raw text
 // of the method that belongs at position i in the expected order.
-func (r *Reorderer) buildSlotReplacements(region structRegion) ([]slotReplacement, error) {
+func (r *Reorderer) buildSlotReplacements(region structRegion) ([]Replacement, error) {
 	// Build name → rawText lookup from blocks (original source order).
 	byName := make(map[string]string, len(region.blocks))
 	for _, b := range region.blocks {
@@ -113,16 +138,17 @@ func (r *Reorderer) buildSlotReplacements(region structRegion) ([]slotReplacemen
 		return nil, fmt.Errorf("method count mismatch: %d expected vs %d blocks", len(expectedOrder), len(region.blocks))
 	}
 
-	reps := make([]slotReplacement, len(region.blocks))
+	reps := make([]Replacement, len(region.blocks))
 	for i, block := range region.blocks {
 		newText, ok := byName[expectedOrder[i].Name]
 		if !ok {
 			return nil, fmt.Errorf("method %s not found in source map", expectedOrder[i].Name)
 		}
-		reps[i] = slotReplacement{
-			start: r.fset.Position(block.StartPos).Offset,
-			end:   r.fset.Position(block.EndPos).Offset,
-			text:  newText,
+		reps[i] = Replacement{
+			StructName: region.name,
+			Start:      r.fset.Position(block.StartPos).Offset,
+			End:        r.fset.Position(block.EndPos).Offset,
+			Text:       newText,
 		}
 	}
 	return reps, nil
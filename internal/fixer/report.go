@@ -0,0 +1,83 @@
+package fixer
+
+import (
+	"go/token"
+
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+// ReportFile converts the result into its machine-readable form.
+func (r *Result) ReportFile() *report.File {
+	file := &report.File{
+		Path:       r.FilePath,
+		Violations: []report.Violation{},
+		Fixed:      r.Fixed,
+		Moves:      []report.Move{},
+		Structs:    []report.Struct{},
+	}
+	if r.Error != nil {
+		file.Error = r.Error.Error()
+	}
+	if r.Report == nil {
+		return file
+	}
+
+	for _, v := range r.Report.Violations {
+		file.Violations = append(file.Violations, report.Violation{
+			Type:     v.Type.Name(),
+			Message:  v.Message,
+			Position: reportPosition(v.Position),
+			Struct:   v.StructName,
+			Method:   v.MethodName,
+			Target:   v.SuggestedFix.TargetName,
+		})
+	}
+
+	if r.Fixed {
+		for _, rep := range r.Replacements {
+			file.Edits = append(file.Edits, report.Edit{
+				Struct: rep.StructName,
+				Offset: rep.Start,
+				Length: rep.End - rep.Start,
+				Text:   rep.Text,
+			})
+		}
+	}
+
+	for _, order := range r.Report.Structs {
+		file.Structs = append(file.Structs, report.Struct{
+			Name:     order.StructName,
+			Position: reportPosition(order.Position),
+			Current:  order.Current,
+			Expected: order.Expected,
+		})
+	}
+
+	for _, order := range r.Report.Orders {
+		slots := make(map[string]int, len(order.Expected))
+		for i, name := range order.Expected {
+			slots[name] = i
+		}
+		for from, name := range order.Current {
+			if to := slots[name]; to != from {
+				file.Moves = append(file.Moves, report.Move{
+					Struct: order.StructName,
+					Method: name,
+					From:   from,
+					To:     to,
+				})
+			}
+		}
+	}
+	return file
+}
+
+// reportPosition converts a token.Position into a report.Position.
+func reportPosition(pos token.Position) report.Position {
+	return report.Position{
+		Filename: pos.Filename,
+		Offset:   pos.Offset,
+		Line:     pos.Line,
+		Column:   pos.Column,
+	}
+}
@@ -0,0 +1,101 @@
+package fixer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/fixer"
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+func TestResult_ReportFile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	result := fixer.NewFixer(cfg).ProcessFile(testdataPath("src", "mixed_violations.go"))
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+
+	file := result.ReportFile()
+	if file.Path != result.FilePath || !file.Fixed {
+		t.Errorf("unexpected file header: %+v", file)
+	}
+	if len(file.Violations) != 3 {
+		t.Fatalf("expected 3 violations, got %d", len(file.Violations))
+	}
+
+	first := file.Violations[0]
+	if first.Type != "exported" || first.Struct != "Engine" || first.Method != "warmUp" ||
+		first.Target != "Run" || first.Position.Line != 5 || first.Position.Column != 1 {
+		t.Errorf("unexpected first violation: %+v", first)
+	}
+
+	// warmUp, Run, NewInstance, status, Stop -> NewInstance, Run, Stop, warmUp, status
+	want := []report.Move{
+		{Struct: "Engine", Method: "warmUp", From: 0, To: 3},
+		{Struct: "Engine", Method: "NewInstance", From: 2, To: 0},
+		{Struct: "Engine", Method: "status", From: 3, To: 4},
+		{Struct: "Engine", Method: "Stop", From: 4, To: 2},
+	}
+	if len(file.Moves) != len(want) {
+		t.Fatalf("expected %d moves, got %+v", len(want), file.Moves)
+	}
+	for i := range want {
+		if file.Moves[i] != want[i] {
+			t.Errorf("move %d = %+v, want %+v", i, file.Moves[i], want[i])
+		}
+	}
+
+	if len(file.Structs) != 1 || file.Structs[0].Name != "Engine" || !file.Structs[0].NeedsReordering() {
+		t.Errorf("expected Engine to need reordering, got %+v", file.Structs)
+	}
+}
+
+func TestResult_ReportFile_Error(t *testing.T) {
+	result := &fixer.Result{FilePath: "broken.go", Error: errors.New("failed to parse file")}
+
+	file := result.ReportFile()
+	if file.Error != "failed to parse file" {
+		t.Errorf("expected error message, got %q", file.Error)
+	}
+	if file.Violations == nil || file.Moves == nil || file.Structs == nil {
+		t.Error("expected empty, non-nil violations, moves and structs")
+	}
+}
+
+func TestResult_Replacements(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		standalone bool
+	}{
+		{"reorder", "mixed_violations.go", false},
+		{"standalone constructors", "standalone_constructors.go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Fix = true
+			cfg.StandaloneConstructors = tt.standalone
+			result := fixer.NewFixer(cfg).ProcessFile(testdataPath("src", tt.file))
+			if result.Error != nil || !result.Fixed {
+				t.Fatalf("expected a fix, got error %v", result.Error)
+			}
+			if len(result.Replacements) == 0 {
+				t.Fatal("expected replacements")
+			}
+
+			applied := fixer.ApplyReplacements(result.OriginalContent, result.Replacements)
+			if string(applied) != string(result.FixedContent) {
+				t.Errorf("replacements do not reproduce the fixed content:\n%s", applied)
+			}
+
+			edits := result.ReportFile().Edits
+			if len(edits) != len(result.Replacements) {
+				t.Errorf("expected %d edits, got %d", len(result.Replacements), len(edits))
+			}
+		})
+	}
+}
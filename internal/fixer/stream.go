@@ -0,0 +1,203 @@
+package fixer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// unit is a piece of work processed by a single worker: one file, or all the
+// files of a directory in package mode.
+type unit func() []*Result
+
+// ProcessFilesFunc processes the named Go files and calls fn with their
+// results in the given order. Files are processed even if a directory walk
+// would skip them. A file whose configuration enables package mode is
+// processed together with the files of its directory that a walk would
+// process, once per directory, but only the results of the named files are
+// passed to fn. Up to Jobs files or packages are processed concurrently.
+func (f *Fixer) ProcessFilesFunc(paths []string, fn func(*Result)) {
+	var units []unit
+	seen := make(map[string]bool)
+	named := make(map[string][]string)
+	for _, path := range paths {
+		if seen[filepath.Clean(path)] {
+			continue
+		}
+		seen[filepath.Clean(path)] = true
+
+		dir := filepath.Dir(path)
+		sub, err := f.fixerFor(dir)
+		switch {
+		case err != nil:
+			units = append(units, func() []*Result {
+				return []*Result{{FilePath: path, Error: err}}
+			})
+		case sub.config.Package:
+			if _, ok := named[dir]; !ok {
+				units = append(units, func() []*Result {
+					return f.processNamedPackage(sub, dir, named[dir])
+				})
+			}
+			named[dir] = append(named[dir], path)
+		default:
+			units = append(units, func() []*Result {
+				return []*Result{sub.ProcessFile(path)}
+			})
+		}
+	}
+
+	f.runUnits(units, fn)
+}
+
+// processNamedPackage processes the named files of dir in package mode,
+// together with the other files of dir a directory walk would process, and
+// returns the results of the named files in order. A fix that would also
+// change a file that was not named is refused, since writing only the named
+// files would leave the package broken.
+func (f *Fixer) processNamedPackage(sub *Fixer, dir string, named []string) []*Result {
+	index := make(map[string]int, len(named))
+	paths := make([]string, len(named))
+	for i, path := range named {
+		paths[i] = filepath.Clean(path)
+		index[paths[i]] = i
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		results := make([]*Result, len(named))
+		for i, path := range named {
+			results[i] = &Result{FilePath: path, Error: fmt.Errorf("cannot list package files: %w", err)}
+		}
+		return results
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if _, ok := index[filepath.Clean(path)]; ok || entry.IsDir() || filepath.Ext(path) != ".go" {
+			continue
+		}
+		if !f.skip(path, false) {
+			paths = append(paths, path)
+		}
+	}
+
+	// The files are processed in the order of a walk, so that the fix is
+	// the same as when the directory is processed.
+	sort.Strings(paths)
+	results := make([]*Result, len(named))
+	var others []string
+	for _, result := range sub.ProcessPackage(paths) {
+		if i, ok := index[filepath.Clean(result.FilePath)]; ok {
+			result.FilePath = named[i]
+			results[i] = result
+		} else if result.Fixed {
+			others = append(others, result.FilePath)
+		}
+	}
+	if len(others) > 0 {
+		for _, result := range results {
+			if result.Fixed {
+				result.Error = fmt.Errorf("fix also changes %s, which was not named; process the directory instead", strings.Join(others, ", "))
+				result.Fixed = false
+			}
+		}
+	}
+	return results
+}
+
+// processFilesFunc processes the given Go files and calls fn with each
+// result. Every directory is processed with the fixer for its configuration,
+// as a package if that configuration enables package mode. Up to Jobs files
+// or packages are processed concurrently; results are passed to fn on the
+// calling goroutine, sorted by path with the results of a package together
+// at the position of its first file.
+func (f *Fixer) processFilesFunc(paths []string, fn func(*Result)) {
+	paths = append([]string(nil), paths...)
+	sort.Strings(paths)
+
+	byDir := make(map[string][]string)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		byDir[dir] = append(byDir[dir], path)
+	}
+
+	var units []unit
+	started := make(map[string]bool)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		sub, err := f.fixerFor(dir)
+		switch {
+		case err != nil:
+			units = append(units, func() []*Result {
+				return []*Result{{FilePath: path, Error: err}}
+			})
+		case sub.config.Package:
+			if !started[dir] {
+				started[dir] = true
+				files := byDir[dir]
+				units = append(units, func() []*Result {
+					return sub.ProcessPackage(files)
+				})
+			}
+		default:
+			units = append(units, func() []*Result {
+				return []*Result{sub.ProcessFile(path)}
+			})
+		}
+	}
+
+	f.runUnits(units, fn)
+}
+
+// runUnits runs units on a pool of Jobs workers and calls fn with their
+// results in unit order. At most twice as many units as there are workers
+// hold results at a time, so memory stays bounded however many files there
+// are.
+func (f *Fixer) runUnits(units []unit, fn func(*Result)) {
+	workers := f.config.Jobs
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers == 1 {
+		for _, u := range units {
+			for _, result := range u() {
+				fn(result)
+			}
+		}
+		return
+	}
+
+	type job struct {
+		run  unit
+		done chan []*Result
+	}
+	jobs := make(chan job)
+	pending := make(chan chan []*Result, workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for j := range jobs {
+				j.done <- j.run()
+			}
+		}()
+	}
+
+	go func() {
+		for _, u := range units {
+			done := make(chan []*Result, 1)
+			pending <- done
+			jobs <- job{run: u, done: done}
+		}
+		close(jobs)
+		close(pending)
+	}()
+
+	for done := range pending {
+		for _, result := range <-done {
+			fn(result)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package fixer
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/vajrock/funcorder-fix/internal/detector"
+)
+
+// loadMode is what typedConstructors needs from go/packages.
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+	packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo
+
+// newDetector creates a Detector for file, which was read from path. When
+// TypeCheck is enabled the detector classifies constructors using the type
+// information of the package file belongs to.
+func (f *Fixer) newDetector(fset *token.FileSet, path string, file *ast.File) *detector.Detector {
+	det := detector.NewDetector(fset, f.config)
+	if f.config.TypeCheck {
+		det.SetTypedConstructors(f.typedConstructors(filepath.Dir(path), file.Name.Name))
+	}
+	return det
+}
+
+// typedConstructors returns the constructors of package pkgName in dir as
+// resolved by the type checker. Each directory is loaded once. It returns nil
+// if the directory cannot be loaded, so that the name-based rule applies.
+func (f *Fixer) typedConstructors(dir, pkgName string) detector.TypedConstructors {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	byName, ok := f.typed[dir]
+	if !ok {
+		byName = f.loadTypedConstructors(dir)
+		f.typed[dir] = byName
+	}
+	return byName[pkgName]
+}
+
+// loadTypedConstructors type-checks the packages in dir, including test
+// packages, and resolves their constructors keyed by package name. Files with
+// errors are left out.
+func (f *Fixer) loadTypedConstructors(dir string) map[string]detector.TypedConstructors {
+	cfg := f.packagesConfig(loadMode)
+	cfg.Dir = dir
+	cfg.Tests = true
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil
+	}
+
+	byName := make(map[string]detector.TypedConstructors)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+
+		broken := make(map[string]bool)
+		for _, e := range pkg.Errors {
+			broken[errorFile(e.Pos)] = true
+		}
+		for _, e := range pkg.TypeErrors {
+			broken[e.Fset.Position(e.Pos).Filename] = true
+		}
+		skip := func(file *ast.File) bool {
+			return broken[pkg.Fset.Position(file.Package).Filename]
+		}
+
+		tc := byName[pkg.Name]
+		if tc == nil {
+			tc = make(detector.TypedConstructors)
+			byName[pkg.Name] = tc
+		}
+		for key, name := range detector.ResolveConstructors(pkg.Syntax, pkg.TypesInfo, skip) {
+			tc[key] = name
+		}
+	}
+	return byName
+}
+
+// errorFile extracts the file name from a packages.Error position of the form
+// "file:line:col" or "file:line".
+func errorFile(pos string) string {
+	for i := 0; i < 2; i++ {
+		j := strings.LastIndexByte(pos, ':')
+		if j < 0 || strings.Trim(pos[j+1:], "0123456789") != "" {
+			break
+		}
+		pos = pos[:j]
+	}
+	return pos
+}
@@ -0,0 +1,230 @@
+package fixer_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/fixer"
+)
+
+// writeModule creates a module named example.com/shop in a temporary
+// directory with the given files and returns its path.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/shop\n\ngo 1.21\n"
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const orderSrc = `package shop
+
+type Order struct{ id int }
+
+func (o *Order) Origin() string { return "" }
+
+func (o *Order) Build() *Order { return o }
+`
+
+func TestProcessFile_TypeCheck(t *testing.T) {
+	dir := writeModule(t, map[string]string{"order.go": orderSrc})
+	path := filepath.Join(dir, "order.go")
+
+	// By name, Origin is a constructor and Build is not.
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	result := fixer.NewFixer(cfg).ProcessFile(path)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if result.Violations != 0 {
+		t.Errorf("expected 0 violations with the prefix rule, got %d", result.Violations)
+	}
+
+	// By type, Build constructs an Order and Origin does not.
+	cfg.TypeCheck = true
+	result = fixer.NewFixer(cfg).ProcessFile(path)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if result.Violations != 1 {
+		t.Fatalf("expected 1 violation with type information, got %d", result.Violations)
+	}
+	fixed := string(result.FixedContent)
+	if strings.Index(fixed, "Build()") > strings.Index(fixed, "Origin()") {
+		t.Errorf("expected Build to be moved before Origin, got:\n%s", fixed)
+	}
+}
+
+func TestProcessFile_TypeCheckFallback(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"order.go": orderSrc,
+		"broken.go": `package shop
+
+var broken int = "not an int"
+
+type Copier struct{}
+
+func (c *Copier) Run() {}
+
+func (c *Copier) NewCopy() *Copier { return c }
+`,
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.TypeCheck = true
+	f := fixer.NewFixer(cfg)
+
+	// broken.go does not type-check, so NewCopy is matched by its name.
+	if result := f.ProcessFile(filepath.Join(dir, "broken.go")); result.Violations != 1 {
+		t.Errorf("broken.go: expected 1 violation from the prefix rule, got %d (err: %v)",
+			result.Violations, result.Error)
+	}
+	if result := f.ProcessFile(filepath.Join(dir, "order.go")); result.Violations != 1 {
+		t.Errorf("order.go: expected 1 violation from type information, got %d (err: %v)",
+			result.Violations, result.Error)
+	}
+}
+
+func TestProcessPackage_TypeCheck(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"order.go": `package shop
+
+type Order struct{ id int }
+`,
+		"order_build.go": `package shop
+
+func (o *Order) Origin() string { return "" }
+
+func (o *Order) Build() *Order { return o }
+`,
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.Package = true
+	cfg.TypeCheck = true
+	results := fixer.NewFixer(cfg).ProcessDirectory(dir)
+
+	violations := 0
+	for _, result := range results {
+		if result.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", result.FilePath, result.Error)
+		}
+		violations += result.Violations
+	}
+	if violations != 1 {
+		t.Errorf("expected 1 violation, got %d", violations)
+	}
+}
+
+func TestProcessPackage_VerifyTypes(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"order.go": orderSrc,
+		"order_helpers.go": `package shop
+
+type Cart struct{ orders []*Order }
+
+func (c *Cart) total() int { return len(c.orders) }
+
+func (c *Cart) Total() int { return c.total() }
+
+func (o *Order) total() int { return o.id }
+
+func (o *Order) Total() int { return o.total() }
+`,
+		"order_test.go": "package shop\n\nfunc (o *Order) testID() int { return o.id }\n",
+	})
+	paths := []string{
+		filepath.Join(dir, "order.go"),
+		filepath.Join(dir, "order_helpers.go"),
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Fix = true
+	cfg.VerifyTypes = true
+	result := fixer.NewFixer(cfg).ProcessFile(paths[1])
+	if result.Error != nil || !result.Fixed {
+		t.Fatalf("expected a verified fix, got %+v", result)
+	}
+
+	cfg.MoveMethods = true
+	cfg.Package = true
+	for _, r := range fixer.NewFixer(cfg).ProcessPackage(paths) {
+		if r.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", r.FilePath, r.Error)
+		}
+		if !r.Fixed {
+			t.Errorf("%s: expected the methods to be moved", r.FilePath)
+		}
+	}
+
+	// Nothing is written.
+	if content, _ := os.ReadFile(paths[0]); string(content) != orderSrc {
+		t.Errorf("expected order.go to be left unchanged, got:\n%s", content)
+	}
+}
+
+func TestProcessPatterns(t *testing.T) {
+	const src = "package %s\n\ntype S struct{}\n\nfunc (s *S) b() {}\n\nfunc (s *S) A() {}\n"
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/shop\n\ngo 1.21\n",
+		"a.go":                  fmt.Sprintf(src, "shop"),
+		"a_test.go":             "package shop\n",
+		"integration.go":        "//go:build integration\n\npackage shop\n",
+		"internal/cart/cart.go": fmt.Sprintf(src, "cart"),
+		"testdata/fixture.go":   fmt.Sprintf(src, "fixture"),
+		"tools/go.mod":          "module example.com/shop/tools\n\ngo 1.21\n",
+		"tools/tool.go":         fmt.Sprintf(src, "tools"),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	processed := func(cfg *config.Config, patterns ...string) string {
+		t.Helper()
+		results, err := fixer.NewFixer(cfg).ProcessPatterns(dir, patterns)
+		if err != nil {
+			t.Fatalf("ProcessPatterns(%v): %v", patterns, err)
+		}
+		var paths []string
+		for _, result := range results {
+			rel, _ := filepath.Rel(dir, result.FilePath)
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return strings.Join(paths, " ")
+	}
+
+	// Nested modules and testdata are not part of ./..., and files excluded
+	// by build constraints are skipped.
+	cfg := config.DefaultConfig()
+	if got := processed(cfg, "./..."); got != "a.go a_test.go internal/cart/cart.go" {
+		t.Errorf("./... resolved to %q", got)
+	}
+	if got := processed(cfg, "example.com/shop/internal/..."); got != "internal/cart/cart.go" {
+		t.Errorf("import path pattern resolved to %q", got)
+	}
+
+	cfg.BuildTags = []string{"integration"}
+	if got := processed(cfg, "."); got != "a.go a_test.go integration.go" {
+		t.Errorf("with -tags=integration resolved to %q", got)
+	}
+
+	if _, err := fixer.NewFixer(cfg).ProcessPatterns(dir, []string{"./testdata/..."}); err == nil {
+		t.Error("expected an error for a pattern matching no packages")
+	}
+}
@@ -0,0 +1,186 @@
+package fixer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+	"github.com/vajrock/funcorder-fix/internal/detector"
+)
+
+// ErrVerification is returned, wrapped in an error describing the failed
+// check, when a fix does more than reorder declarations.
+var ErrVerification = errors.New("fix verification failed")
+
+// maxListed is the number of differing items an error lists.
+const maxListed = 3
+
+// verifyFix checks that fixed, the fixed contents of the files at paths, is
+// a pure reordering of srcs: the fixed files parse, hold the same top-level
+// declarations and comments, have no violations left, and fixing them again
+// changes nothing. The files are checked together in package mode, where
+// methods may move between them. With VerifyTypes the package is
+// type-checked as well.
+func (f *Fixer) verifyFix(paths []string, srcs, fixed [][]byte) error {
+	beforeFset := token.NewFileSet()
+	before, err := parseFiles(beforeFset, paths, srcs)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	after, err := parseFiles(fset, paths, fixed)
+	if err != nil {
+		return fmt.Errorf("%w: fixed source does not parse: %v", ErrVerification, err)
+	}
+
+	if err := compareItems("declaration", declTexts(beforeFset, before, srcs), declTexts(fset, after, fixed)); err != nil {
+		return err
+	}
+	if err := compareItems("comment", commentTexts(before), commentTexts(after)); err != nil {
+		return err
+	}
+
+	det := f.newDetector(fset, paths[0], after[0])
+	var remaining []*detector.Violation
+	if f.config.Package {
+		for _, report := range det.DetectPackage(after, paths) {
+			remaining = append(remaining, report.Violations...)
+		}
+	} else {
+		remaining = det.Detect(after[0], paths[0]).Violations
+	}
+	// Methods outside the file of their struct are only moved on request.
+	if !f.config.MoveMethods {
+		remaining = slices.DeleteFunc(remaining, func(v *detector.Violation) bool {
+			return v.Type == config.ViolationMisplaced
+		})
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("%w: violations remain after fixing (%d), first: %s", ErrVerification, len(remaining), remaining[0])
+	}
+
+	var again [][]byte
+	if f.config.Package {
+		again, err = f.reorderPackageFiles(fset, det, after, paths, fixed)
+	} else {
+		var out []byte
+		out, _, err = f.reorderFile(fset, paths[0], after[0], fixed[0])
+		again = [][]byte{out}
+	}
+	if err != nil {
+		return fmt.Errorf("%w: second fix pass: %v", ErrVerification, err)
+	}
+	for i := range fixed {
+		if !bytes.Equal(again[i], fixed[i]) {
+			return fmt.Errorf("%w: second fix pass changes %s", ErrVerification, paths[i])
+		}
+	}
+
+	if f.config.VerifyTypes {
+		return f.verifyTypes(paths, srcs, fixed)
+	}
+	return nil
+}
+
+// parseFiles parses the files at paths with the given contents into fset.
+func parseFiles(fset *token.FileSet, paths []string, contents [][]byte) ([]*ast.File, error) {
+	files := make([]*ast.File, len(paths))
+	for i, path := range paths {
+		file, err := parser.ParseFile(fset, path, contents[i], parser.ParseComments|parser.AllErrors)
+		if err != nil {
+			return nil, err
+		}
+		files[i] = file
+	}
+	return files, nil
+}
+
+// declTexts returns the source text of every top-level declaration of files,
+// whose contents are given, without its doc comment.
+func declTexts(fset *token.FileSet, files []*ast.File, contents [][]byte) []string {
+	var texts []string
+	for i, file := range files {
+		for _, decl := range file.Decls {
+			start := fset.Position(decl.Pos()).Offset
+			end := fset.Position(decl.End()).Offset
+			texts = append(texts, string(contents[i][start:end]))
+		}
+	}
+	return texts
+}
+
+// commentTexts returns the text of every comment of files.
+func commentTexts(files []*ast.File) []string {
+	var texts []string
+	for _, file := range files {
+		for _, cg := range file.Comments {
+			for _, c := range cg.List {
+				texts = append(texts, c.Text)
+			}
+		}
+	}
+	return texts
+}
+
+// compareItems returns an error listing the items of kind that are missing
+// from after or were added to it, if before and after are not equal as
+// multisets.
+func compareItems(kind string, before, after []string) error {
+	missing, added := diffItems(before, after)
+	if len(missing) == 0 && len(added) == 0 {
+		return nil
+	}
+
+	var details []string
+	if len(missing) > 0 {
+		details = append(details, fmt.Sprintf("missing %ss (%d): %s", kind, len(missing), listItems(missing)))
+	}
+	if len(added) > 0 {
+		details = append(details, fmt.Sprintf("added %ss (%d): %s", kind, len(added), listItems(added)))
+	}
+	return fmt.Errorf("%w: %s", ErrVerification, strings.Join(details, "; "))
+}
+
+// diffItems returns the items of before that are missing from after and the
+// items of after that were added, counting duplicates.
+func diffItems(before, after []string) (missing, added []string) {
+	counts := make(map[string]int, len(before))
+	for _, item := range before {
+		counts[item]++
+	}
+	for _, item := range after {
+		counts[item]--
+	}
+	for item, n := range counts {
+		for ; n > 0; n-- {
+			missing = append(missing, item)
+		}
+		for ; n < 0; n++ {
+			added = append(added, item)
+		}
+	}
+	return missing, added
+}
+
+// listItems returns the first line of up to maxListed items, sorted, for use
+// in an error message.
+func listItems(items []string) string {
+	sort.Strings(items)
+	var lines []string
+	for i, item := range items {
+		if i == maxListed {
+			lines = append(lines, "...")
+			break
+		}
+		line, _, _ := strings.Cut(item, "\n")
+		lines = append(lines, fmt.Sprintf("%q", line))
+	}
+	return strings.Join(lines, ", ")
+}
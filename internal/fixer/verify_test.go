@@ -0,0 +1,151 @@
+package fixer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/internal/config"
+)
+
+func TestVerifyFix(t *testing.T) {
+	const src = `package p
+
+type S struct{}
+
+// b is unexported.
+func (s *S) b() {}
+
+// A is exported.
+func (s *S) A() {}
+`
+	const fixed = `package p
+
+type S struct{}
+
+// A is exported.
+func (s *S) A() {}
+
+// b is unexported.
+func (s *S) b() {}
+`
+
+	tests := []struct {
+		name  string
+		fixed string
+		want  string
+	}{
+		{"reordered", fixed, ""},
+		{"syntax error", strings.Replace(fixed, "func (s *S) b() {}", "func (s *S) b() {", 1), "does not parse"},
+		{"dropped method", strings.Replace(fixed, "func (s *S) b() {}\n", "", 1), "missing declarations (1): \"func (s *S) b() {}\""},
+		{"edited method", strings.Replace(fixed, "A() {}", "A() { println() }", 1), "added declarations (1)"},
+		{"edited comment", strings.Replace(fixed, "// A is exported.", "// A is public.", 1), "missing comments (1): \"// A is exported.\""},
+		{"not fixed", src, "violations remain after fixing (1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFixer(config.DefaultConfig())
+			err := f.verifyFix([]string{"p.go"}, [][]byte{[]byte(src)}, [][]byte{[]byte(tt.fixed)})
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrVerification) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyFix_Package(t *testing.T) {
+	paths := []string{"s.go", "helpers.go"}
+	srcs := [][]byte{
+		[]byte("package p\n\ntype S struct{}\n\nfunc (s *S) A() {}\n"),
+		[]byte("package p\n\nfunc (s *S) B() {}\n"),
+	}
+	moved := [][]byte{
+		[]byte("package p\n\ntype S struct{}\n\nfunc (s *S) A() {}\n\nfunc (s *S) B() {}\n"),
+		[]byte("package p\n"),
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Package = true
+
+	// Methods outside the file of their struct are left there without
+	// MoveMethods, and may move between files with it.
+	if err := NewFixer(cfg).verifyFix(paths, srcs, srcs); err != nil {
+		t.Errorf("unexpected error without MoveMethods: %v", err)
+	}
+	cfg.MoveMethods = true
+	if err := NewFixer(cfg).verifyFix(paths, srcs, srcs); !errors.Is(err, ErrVerification) {
+		t.Errorf("expected the misplaced method to fail verification, got %v", err)
+	}
+	if err := NewFixer(cfg).verifyFix(paths, srcs, moved); err != nil {
+		t.Errorf("unexpected error with MoveMethods: %v", err)
+	}
+}
+
+func TestVerifyTypes(t *testing.T) {
+	const src = `package shop
+
+type Order struct{ id int }
+
+func (o *Order) id2() int { return o.id * 2 }
+
+func (o *Order) ID() int { return o.id }
+`
+	const fixed = `package shop
+
+type Order struct{ id int }
+
+func (o *Order) ID() int { return o.id }
+
+func (o *Order) id2() int { return o.id * 2 }
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "order.go")
+	for name, content := range map[string]string{
+		"go.mod":   "module example.com/shop\n\ngo 1.21\n",
+		"order.go": src,
+		"other.go": "package shop\n\nfunc Total(o *Order) int { return o.ID() }\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		fixed string
+		want  string
+	}{
+		{"reordered", fixed, ""},
+		{"type error", strings.Replace(fixed, "return o.id }", `return "id" }`, 1), "fix introduces type errors (1)"},
+		{"changed method", strings.Replace(fixed, "id2() int { return o.id * 2 }", "id2() int64 { return int64(o.id) * 2 }", 1), "missing method sets (1)"},
+		{"renamed local", strings.Replace(fixed, "func (o *Order) id2() int { return o.id * 2 }", "func (order *Order) id2() int { return order.id * 2 }", 1), "objects"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFixer(config.DefaultConfig())
+			err := f.verifyTypes([]string{path}, [][]byte{[]byte(src)}, [][]byte{[]byte(tt.fixed)})
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrVerification) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// The fixed source is overlaid, never written.
+	if content, _ := os.ReadFile(path); string(content) != src {
+		t.Errorf("expected %s to be left unchanged, got:\n%s", path, content)
+	}
+}
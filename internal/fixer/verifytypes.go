@@ -0,0 +1,184 @@
+package fixer
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/vajrock/funcorder-fix/internal/detector"
+)
+
+// typeSummary is what type-checking a package found, described without
+// absolute positions, so that summaries before and after a fix that only
+// moves declarations are equal.
+type typeSummary struct {
+	// errors lists the parse and type errors of the package without their
+	// positions.
+	errors []string
+
+	// methodSets lists the method sets of the package-level types and of
+	// pointers to them.
+	methodSets []string
+
+	// objects lists every declared object with its type and its offset
+	// within the top-level declaration that declares it.
+	objects []string
+}
+
+// verifyTypes type-checks the package of the files at paths twice, with the
+// contents srcs and with the contents fixed overlaid on the files on disk,
+// and returns an error if the fix introduces type errors or changes the
+// method sets or the objects of the package. Nothing is written.
+func (f *Fixer) verifyTypes(paths []string, srcs, fixed [][]byte) error {
+	before, err := f.summarizeTypes(paths, srcs)
+	if err != nil {
+		return fmt.Errorf("%w: type-checking before the fix: %v", ErrVerification, err)
+	}
+	after, err := f.summarizeTypes(paths, fixed)
+	if err != nil {
+		return fmt.Errorf("%w: type-checking after the fix: %v", ErrVerification, err)
+	}
+
+	if _, added := diffItems(before.errors, after.errors); len(added) > 0 {
+		return fmt.Errorf("%w: fix introduces type errors (%d): %s", ErrVerification, len(added), listItems(added))
+	}
+	if err := compareItems("method set", before.methodSets, after.methodSets); err != nil {
+		return err
+	}
+	return compareItems("object", before.objects, after.objects)
+}
+
+// summarizeTypes type-checks the package in the directory of paths, including
+// its tests, with contents overlaid on the files at paths.
+func (f *Fixer) summarizeTypes(paths []string, contents [][]byte) (*typeSummary, error) {
+	overlay := make(map[string][]byte, len(paths))
+	for i, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		overlay[abs] = contents[i]
+	}
+
+	cfg := f.packagesConfig(loadMode)
+	cfg.Dir = filepath.Dir(paths[0])
+	cfg.Tests = true
+	cfg.Overlay = overlay
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &typeSummary{}
+	sources := &sourceReader{overlay: overlay, files: make(map[string][]byte)}
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") || pkg.Types == nil {
+			continue
+		}
+		// List errors repeat the compiler output, positions included.
+		for _, e := range pkg.Errors {
+			if e.Kind != packages.ListError {
+				summary.errors = append(summary.errors, pkg.ID+": "+e.Msg)
+			}
+		}
+		summary.addMethodSets(pkg)
+		summary.addObjects(pkg, sources)
+	}
+	return summary, nil
+}
+
+// addMethodSets adds the method sets of the types declared at package level
+// in pkg, and of pointers to them.
+func (s *typeSummary) addMethodSets(pkg *packages.Package) {
+	qual := types.RelativeTo(pkg.Types)
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		for _, t := range []types.Type{tn.Type(), types.NewPointer(tn.Type())} {
+			mset := types.NewMethodSet(t)
+			methods := make([]string, mset.Len())
+			for i := range methods {
+				methods[i] = types.ObjectString(mset.At(i).Obj(), qual)
+			}
+			s.methodSets = append(s.methodSets, fmt.Sprintf("%s: %s {%s}",
+				pkg.ID, types.TypeString(t, qual), strings.Join(methods, "; ")))
+		}
+	}
+}
+
+// addObjects adds every object defined in the files of pkg, located by the
+// top-level declaration containing it and its offset within.
+func (s *typeSummary) addObjects(pkg *packages.Package, sources *sourceReader) {
+	qual := types.RelativeTo(pkg.Types)
+	for ident, obj := range pkg.TypesInfo.Defs {
+		if obj == nil {
+			continue
+		}
+		file := detector.FileContaining(pkg.Syntax, ident.Pos())
+		if file == nil {
+			continue
+		}
+		decl := declAt(file, ident.Pos())
+		if decl == nil {
+			continue
+		}
+		tf := pkg.Fset.File(ident.Pos())
+		src := sources.read(tf.Name())
+		start, end := tf.Offset(decl.Pos()), tf.Offset(decl.End())
+		if src == nil || end > len(src) {
+			continue
+		}
+		s.objects = append(s.objects, fmt.Sprintf("%s: %s at offset %d of %s",
+			pkg.ID, types.ObjectString(obj, qual), tf.Offset(ident.Pos())-start, declKey(src[start:end])))
+	}
+}
+
+// declAt returns the top-level declaration of file containing pos.
+func declAt(file *ast.File, pos token.Pos) ast.Decl {
+	i := sort.Search(len(file.Decls), func(i int) bool {
+		return file.Decls[i].End() > pos
+	})
+	if i < len(file.Decls) && file.Decls[i].Pos() <= pos {
+		return file.Decls[i]
+	}
+	return nil
+}
+
+// declKey identifies the source text of a declaration by its first line and
+// a hash of the whole text.
+func declKey(text []byte) string {
+	line, _, _ := strings.Cut(string(text), "\n")
+	sum := sha256.Sum256(text)
+	return fmt.Sprintf("%q (%x)", line, sum[:4])
+}
+
+// sourceReader returns the content of the files of a package: the overlay
+// if there is one, the file on disk otherwise.
+type sourceReader struct {
+	overlay map[string][]byte
+	files   map[string][]byte
+}
+
+// read returns the content of the file at path, or nil if it cannot be read.
+func (r *sourceReader) read(path string) []byte {
+	if src, ok := r.overlay[path]; ok {
+		return src
+	}
+	src, ok := r.files[path]
+	if !ok {
+		src, _ = os.ReadFile(path)
+		r.files[path] = src
+	}
+	return src
+}
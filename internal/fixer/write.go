@@ -0,0 +1,102 @@
+package fixer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrModified is returned by WriteResult when the file changed on disk after
+// it was read, so that the fix would overwrite those changes.
+var ErrModified = errors.New("file changed on disk since it was read")
+
+// keptMode are the mode bits a rewritten file keeps.
+const keptMode = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+// writeFileAtomic replaces the file at path, which had the content original
+// when it was read, with content. The check for changes is skipped if
+// original is nil. The content is written to a temporary file in the same
+// directory, synced and renamed over the original, so that a crash leaves
+// either the old or the new file, never a truncated one. Symbolic links are
+// followed.
+//
+// The replacement keeps the permissions of the original, including the
+// setuid, setgid and sticky bits. On Unix it is also given the owner and
+// group of the original when the process may change them, as a file written
+// by another user otherwise ends up owned by the one running the fix.
+func writeFileAtomic(path string, content, original []byte) error {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	if original != nil {
+		if err := checkUnmodified(target, info, original); err != nil {
+			return err
+		}
+	}
+
+	dir := filepath.Dir(target)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if tmp != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.Write(content); err != nil {
+		return err
+	}
+	// Change the owner first, since doing so may clear the setuid and setgid
+	// bits.
+	if err := chown(tmp, info); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(info.Mode() & keptMode); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return err
+	}
+	tmp = nil
+
+	// Sync the directory so that the rename itself survives a crash. Not
+	// every platform can open a directory for syncing, so errors are ignored.
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// checkUnmodified returns ErrModified unless the file at path with info still
+// has the content original. The size is compared first, so that most
+// changes are caught without reading the file.
+func checkUnmodified(path string, info os.FileInfo, original []byte) error {
+	if info.Size() == int64(len(original)) {
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(current, original) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %w", path, ErrModified)
+}
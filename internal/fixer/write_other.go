@@ -0,0 +1,13 @@
+//go:build !unix
+
+package fixer
+
+import (
+	"io/fs"
+	"os"
+)
+
+// chown does nothing on platforms without Unix file ownership.
+func chown(tmp *os.File, info fs.FileInfo) error {
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package fixer
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// chown gives tmp the owner and group of the file described by info. It is
+// not an error if the process is not allowed to.
+func chown(tmp *os.File, info fs.FileInfo) error {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	err := tmp.Chown(int(st.Uid), int(st.Gid))
+	if errors.Is(err, fs.ErrPermission) {
+		return nil
+	}
+	return err
+}
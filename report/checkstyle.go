@@ -0,0 +1,77 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// CheckstyleReporter writes all files as a single Checkstyle XML report with
+// one file element per file and one error element per violation.
+type CheckstyleReporter struct {
+	files []*File
+}
+
+// Add records the result of processing a single file.
+func (r *CheckstyleReporter) Add(file *File) {
+	r.files = append(r.files, file)
+}
+
+// Flush writes the Checkstyle report to w.
+func (r *CheckstyleReporter) Flush(w io.Writer) error {
+	doc := checkstyleDoc{Version: "8.0"}
+	for _, file := range r.files {
+		cf := checkstyleFile{Name: file.Path}
+		if file.Error != "" {
+			cf.Errors = append(cf.Errors, checkstyleError{
+				Severity: "error",
+				Message:  file.Error,
+				Source:   toolName,
+			})
+		}
+		for _, v := range file.Violations {
+			cf.Errors = append(cf.Errors, checkstyleError{
+				Line:     v.Position.Line,
+				Column:   v.Position.Column,
+				Severity: "warning",
+				Message:  v.Message,
+				Source:   toolName + "." + v.Type,
+			})
+		}
+		doc.Files = append(doc.Files, cf)
+	}
+
+	return writeXML(w, doc)
+}
+
+// writeXML writes v as an indented XML document with a header.
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type checkstyleDoc struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
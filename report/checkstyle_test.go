@@ -0,0 +1,66 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+func TestCheckstyleReporter(t *testing.T) {
+	r, err := report.New("checkstyle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Add(&report.File{
+		Path: "a.go",
+		Violations: []report.Violation{
+			{Type: "exported", Message: "unexported method stop should appear after exported method Run",
+				Position: report.Position{Filename: "a.go", Line: 4, Column: 1}},
+			{Type: "constructor", Message: "constructor NewS should appear before exported method Run",
+				Position: report.Position{Filename: "a.go", Line: 9, Column: 1}},
+		},
+	})
+	r.Add(&report.File{Path: "clean.go"})
+	r.Add(&report.File{Path: "b.go", Error: "failed to parse file"})
+
+	var buf bytes.Buffer
+	if err := r.Flush(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Files []struct {
+			Name   string `xml:"name,attr"`
+			Errors []struct {
+				Line     int    `xml:"line,attr"`
+				Column   int    `xml:"column,attr"`
+				Severity string `xml:"severity,attr"`
+				Message  string `xml:"message,attr"`
+				Source   string `xml:"source,attr"`
+			} `xml:"error"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if len(doc.Files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(doc.Files))
+	}
+
+	errs := doc.Files[0].Errors
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors for a.go, got %d", len(errs))
+	}
+	if e := errs[1]; e.Line != 9 || e.Column != 1 || e.Severity != "warning" ||
+		e.Source != "funcorder-fix.constructor" || e.Message != "constructor NewS should appear before exported method Run" {
+		t.Errorf("unexpected error element: %+v", e)
+	}
+	if len(doc.Files[1].Errors) != 0 {
+		t.Errorf("expected no errors for clean.go, got %+v", doc.Files[1].Errors)
+	}
+	if e := doc.Files[2].Errors; len(e) != 1 || e[0].Severity != "error" || e[0].Message != "failed to parse file" {
+		t.Errorf("expected the processing error to be reported, got %+v", e)
+	}
+}
@@ -0,0 +1,48 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GitHubReporter writes one GitHub Actions error workflow command per
+// violation, so that violations are annotated inline on pull requests.
+type GitHubReporter struct {
+	files []*File
+}
+
+// Add records the result of processing a single file.
+func (r *GitHubReporter) Add(file *File) {
+	r.files = append(r.files, file)
+}
+
+// Flush writes the workflow commands to w.
+func (r *GitHubReporter) Flush(w io.Writer) error {
+	for _, file := range r.files {
+		if file.Error != "" {
+			if _, err := fmt.Fprintf(w, "::error file=%s::%s\n",
+				githubProperty(file.Path), githubData(file.Error)); err != nil {
+				return err
+			}
+		}
+		for _, v := range file.Violations {
+			if _, err := fmt.Fprintf(w, "::error file=%s,line=%d,col=%d::%s\n",
+				githubProperty(v.Position.Filename), v.Position.Line, v.Position.Column,
+				githubData(v.Message)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// githubData escapes the message of a workflow command.
+func githubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubProperty escapes a property value of a workflow command.
+func githubProperty(s string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(githubData(s))
+}
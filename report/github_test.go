@@ -0,0 +1,37 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+func TestGitHubReporter(t *testing.T) {
+	r, err := report.New("github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Add(&report.File{
+		Path: "a.go",
+		Violations: []report.Violation{
+			{Message: "unexported method stop should appear after exported method Run",
+				Position: report.Position{Filename: "a.go", Line: 4, Column: 1}},
+			{Message: "100% wrong\nsecond line",
+				Position: report.Position{Filename: "dir,name:x.go", Line: 9, Column: 2}},
+		},
+	})
+	r.Add(&report.File{Path: "b.go", Error: "failed to parse file"})
+
+	var buf bytes.Buffer
+	if err := r.Flush(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "::error file=a.go,line=4,col=1::unexported method stop should appear after exported method Run\n" +
+		"::error file=dir%2Cname%3Ax.go,line=9,col=2::100%25 wrong%0Asecond line\n" +
+		"::error file=b.go::failed to parse file\n"
+	if buf.String() != want {
+		t.Errorf("unexpected output:\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
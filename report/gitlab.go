@@ -0,0 +1,83 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// GitLabReporter writes all violations as a GitLab Code Quality report.
+// Fingerprints are derived from the file, rule, struct and methods of a
+// violation rather than its line, so they stay stable when unrelated code
+// moves the method around.
+type GitLabReporter struct {
+	files []*File
+}
+
+// Add records the result of processing a single file.
+func (r *GitLabReporter) Add(file *File) {
+	r.files = append(r.files, file)
+}
+
+// Flush writes the Code Quality report to w.
+func (r *GitLabReporter) Flush(w io.Writer) error {
+	issues := []gitlabIssue{}
+	for _, file := range r.files {
+		if file.Error != "" {
+			path := gitlabPath(file.Path)
+			issues = append(issues, gitlabIssue{
+				Description: file.Error,
+				CheckName:   toolName,
+				Fingerprint: gitlabFingerprint(path, "error"),
+				Severity:    "major",
+				Location:    gitlabLocation{Path: path, Lines: gitlabLines{Begin: 1}},
+			})
+		}
+		for _, v := range file.Violations {
+			path := gitlabPath(v.Position.Filename)
+			issues = append(issues, gitlabIssue{
+				Description: v.Message,
+				CheckName:   toolName + "." + v.Type,
+				Fingerprint: gitlabFingerprint(path, v.Type, v.Struct, v.Method, v.Target),
+				Severity:    "minor",
+				Location:    gitlabLocation{Path: path, Lines: gitlabLines{Begin: v.Position.Line}},
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// gitlabPath converts a file path into the slash-separated relative form
+// GitLab expects.
+func gitlabPath(path string) string {
+	return strings.TrimPrefix(filepath.ToSlash(path), "./")
+}
+
+// gitlabFingerprint returns a hex SHA-256 digest of parts.
+func gitlabFingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
@@ -0,0 +1,90 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+type gitlabIssue struct {
+	Description string `json:"description"`
+	CheckName   string `json:"check_name"`
+	Fingerprint string `json:"fingerprint"`
+	Severity    string `json:"severity"`
+	Location    struct {
+		Path  string `json:"path"`
+		Lines struct {
+			Begin int `json:"begin"`
+		} `json:"lines"`
+	} `json:"location"`
+}
+
+func flushGitLab(t *testing.T, files ...*report.File) []gitlabIssue {
+	t.Helper()
+	r, err := report.New("gitlab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		r.Add(f)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Flush(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var issues []gitlabIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	return issues
+}
+
+func TestGitLabReporter(t *testing.T) {
+	violation := report.Violation{
+		Type:     "exported",
+		Message:  "unexported method stop should appear after exported method Run",
+		Position: report.Position{Filename: "./pkg/a.go", Line: 4, Column: 1},
+		Struct:   "S",
+		Method:   "stop",
+		Target:   "Run",
+	}
+	other := violation
+	other.Method, other.Position.Line = "halt", 6
+
+	issues := flushGitLab(t,
+		&report.File{Path: "./pkg/a.go", Violations: []report.Violation{violation, other}},
+		&report.File{Path: "b.go", Error: "failed to parse file"},
+	)
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(issues))
+	}
+
+	first := issues[0]
+	if first.CheckName != "funcorder-fix.exported" || first.Severity != "minor" ||
+		first.Location.Path != "pkg/a.go" || first.Location.Lines.Begin != 4 || first.Description != violation.Message {
+		t.Errorf("unexpected issue: %+v", first)
+	}
+	if first.Fingerprint == "" || first.Fingerprint == issues[1].Fingerprint {
+		t.Errorf("expected distinct fingerprints, got %q and %q", first.Fingerprint, issues[1].Fingerprint)
+	}
+	if issues[2].Location.Path != "b.go" || issues[2].Description != "failed to parse file" {
+		t.Errorf("expected the processing error as an issue, got %+v", issues[2])
+	}
+
+	// Moving the method to another line keeps its fingerprint.
+	moved := violation
+	moved.Position.Line = 40
+	again := flushGitLab(t, &report.File{Path: "./pkg/a.go", Violations: []report.Violation{moved}})
+	if again[0].Fingerprint != first.Fingerprint {
+		t.Errorf("fingerprint changed with the line: %q != %q", again[0].Fingerprint, first.Fingerprint)
+	}
+}
+
+func TestGitLabReporter_Empty(t *testing.T) {
+	if issues := flushGitLab(t); issues == nil || len(issues) != 0 {
+		t.Errorf("expected an empty array, got %+v", issues)
+	}
+}
@@ -0,0 +1,29 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONReporter writes all files as a single indented JSON array of File
+// objects.
+type JSONReporter struct {
+	files []*File
+}
+
+// Add records the result of processing a single file.
+func (r *JSONReporter) Add(file *File) {
+	r.files = append(r.files, file)
+}
+
+// Flush writes the recorded files to w.
+func (r *JSONReporter) Flush(w io.Writer) error {
+	files := r.files
+	if files == nil {
+		files = []*File{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(files)
+}
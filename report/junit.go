@@ -0,0 +1,112 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JUnitReporter writes all files as a single JUnit XML report with one test
+// suite per file and one test case per struct. A test case fails when
+// violations are reported for its struct; files that could not be processed
+// are reported as errors.
+type JUnitReporter struct {
+	files []*File
+}
+
+// Add records the result of processing a single file.
+func (r *JUnitReporter) Add(file *File) {
+	r.files = append(r.files, file)
+}
+
+// Flush writes the JUnit report to w.
+func (r *JUnitReporter) Flush(w io.Writer) error {
+	doc := junitTestSuites{Name: toolName}
+	for _, file := range r.files {
+		suite := junitTestSuite{Name: file.Path}
+		if file.Error != "" {
+			suite.Errors++
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      file.Path,
+				ClassName: file.Path,
+				Error:     &junitResult{Message: file.Error, Type: "error"},
+			})
+		}
+		for i := range file.Structs {
+			tc := junitTestCase{Name: file.Structs[i].Name, ClassName: file.Path}
+			if hasViolations(file, file.Structs[i].Name) {
+				suite.Failures++
+				tc.Failure = junitFailure(file, &file.Structs[i])
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		suite.Tests = len(suite.TestCases)
+
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Errors += suite.Errors
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	return writeXML(w, doc)
+}
+
+// junitFailure describes the expected method order of s and the violations
+// reported for it.
+func junitFailure(file *File, s *Struct) *junitResult {
+	var b strings.Builder
+	for _, v := range file.Violations {
+		if v.Struct == s.Name {
+			fmt.Fprintf(&b, "%s:%d:%d: %s\n", v.Position.Filename, v.Position.Line, v.Position.Column, v.Message)
+		}
+	}
+	fmt.Fprintf(&b, "current order: %s\n", strings.Join(s.Current, ", "))
+	fmt.Fprintf(&b, "expected order: %s\n", strings.Join(s.Expected, ", "))
+
+	return &junitResult{
+		Message: "expected method order: " + strings.Join(s.Expected, ", "),
+		Type:    "funcorder",
+		Text:    b.String(),
+	}
+}
+
+// hasViolations reports whether file has violations of the struct name.
+func hasViolations(file *File, name string) bool {
+	for _, v := range file.Violations {
+		if v.Struct == name {
+			return true
+		}
+	}
+	return false
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Failure   *junitResult `xml:"failure,omitempty"`
+	Error     *junitResult `xml:"error,omitempty"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
@@ -0,0 +1,87 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+func TestJUnitReporter(t *testing.T) {
+	r, err := report.New("junit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Add(&report.File{
+		Path: "a.go",
+		Violations: []report.Violation{{
+			Type:     "exported",
+			Message:  "unexported method stop should appear after exported method Run",
+			Position: report.Position{Filename: "a.go", Line: 4, Column: 1},
+			Struct:   "S",
+			Method:   "stop",
+		}},
+		Structs: []report.Struct{
+			{Name: "S", Current: []string{"stop", "Run"}, Expected: []string{"Run", "stop"}},
+			{Name: "T", Current: []string{"Get", "put"}, Expected: []string{"Get", "put"}},
+			// Without violations, for example with a rule disabled, a
+			// different expected order does not fail the test case.
+			{Name: "U", Current: []string{"b", "A"}, Expected: []string{"A", "b"}},
+		},
+	})
+	r.Add(&report.File{Path: "b.go", Error: "failed to parse file"})
+
+	var buf bytes.Buffer
+	if err := r.Flush(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+	var doc struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Suites   []struct {
+			Name      string `xml:"name,attr"`
+			TestCases []struct {
+				Name    string  `xml:"name,attr"`
+				Failure *result `xml:"failure"`
+				Error   *result `xml:"error"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Errors != 1 {
+		t.Errorf("unexpected totals: tests=%d failures=%d errors=%d", doc.Tests, doc.Failures, doc.Errors)
+	}
+	if len(doc.Suites) != 2 || len(doc.Suites[0].TestCases) != 3 {
+		t.Fatalf("expected a suite per file and a test case per struct, got %s", buf.String())
+	}
+
+	failing := doc.Suites[0].TestCases[0]
+	if failing.Name != "S" || failing.Failure == nil {
+		t.Fatalf("expected S to fail, got %+v", failing)
+	}
+	if failing.Failure.Message != "expected method order: Run, stop" {
+		t.Errorf("unexpected failure message %q", failing.Failure.Message)
+	}
+	if !strings.Contains(failing.Failure.Text, "a.go:4:1: unexported method stop") {
+		t.Errorf("expected the violation in the failure text, got %q", failing.Failure.Text)
+	}
+	if passing := doc.Suites[0].TestCases[1]; passing.Name != "T" || passing.Failure != nil {
+		t.Errorf("expected T to pass, got %+v", passing)
+	}
+	if passing := doc.Suites[0].TestCases[2]; passing.Name != "U" || passing.Failure != nil {
+		t.Errorf("expected U without violations to pass, got %+v", passing)
+	}
+	if tc := doc.Suites[1].TestCases; len(tc) != 1 || tc[0].Error == nil || tc[0].Error.Message != "failed to parse file" {
+		t.Errorf("expected the processing error as a test case error, got %+v", tc)
+	}
+}
@@ -0,0 +1,161 @@
+// Package report defines the machine-readable output of funcorder-fix and the
+// reporters that write it. The types in this package are the output schema:
+// tools consuming the JSON output can decode it into them directly.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// File is the result of processing a single Go file.
+type File struct {
+	// Path is the path of the file as given on the command line.
+	Path string `json:"path"`
+
+	// Violations lists the funcorder violations found in the file.
+	Violations []Violation `json:"violations"`
+
+	// Fixed reports whether a fix was produced for the file.
+	Fixed bool `json:"fixed"`
+
+	// Moves lists the method moves that reorder the file, per struct.
+	Moves []Move `json:"moves"`
+
+	// Structs lists the current and expected method order of every struct
+	// with methods in the file.
+	Structs []Struct `json:"structs"`
+
+	// Edits lists the byte edits that turn the file into its fixed content.
+	// It is only set when a fix was produced.
+	Edits []Edit `json:"edits,omitempty"`
+
+	// Error describes why the file could not be processed, if it could not.
+	Error string `json:"error,omitempty"`
+}
+
+// Violation is a single funcorder rule violation.
+type Violation struct {
+	// Type identifies the violated rule: "constructor", "exported",
+	// "misplaced" or "alphabetical".
+	Type string `json:"type"`
+
+	// Message describes the violation.
+	Message string `json:"message"`
+
+	// Position is the position of the violating method.
+	Position Position `json:"position"`
+
+	// Struct is the name of the struct the method belongs to.
+	Struct string `json:"struct"`
+
+	// Method is the name of the violating method.
+	Method string `json:"method"`
+
+	// Target is the name of the method or type the violating method should
+	// be placed relative to.
+	Target string `json:"target,omitempty"`
+}
+
+// Position is a position in a source file. Line and Column are 1-based,
+// Offset is a 0-based byte offset.
+type Position struct {
+	Filename string `json:"filename"`
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// Move describes a method that changes its place among the methods of its
+// struct. Slots are 0-based indexes into the methods the struct declares in
+// the file, in source order.
+type Move struct {
+	// Struct is the name of the struct.
+	Struct string `json:"struct"`
+
+	// Method is the name of the moved method.
+	Method string `json:"method"`
+
+	// From is the slot the method currently occupies.
+	From int `json:"from"`
+
+	// To is the slot the method is moved to.
+	To int `json:"to"`
+}
+
+// Struct is the method order of a struct among the methods it declares in
+// the file.
+type Struct struct {
+	// Name is the name of the struct.
+	Name string `json:"name"`
+
+	// Position is the position of the first method of the struct.
+	Position Position `json:"position"`
+
+	// Current lists the method names in their current order.
+	Current []string `json:"current"`
+
+	// Expected lists the method names in the order the fixer produces.
+	Expected []string `json:"expected"`
+}
+
+// NeedsReordering reports whether the current order differs from the
+// expected one.
+func (s *Struct) NeedsReordering() bool {
+	for i := range s.Current {
+		if i >= len(s.Expected) || s.Current[i] != s.Expected[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Edit replaces Length bytes at Offset of the original file with Text.
+type Edit struct {
+	// Struct is the name of the struct whose methods the edit reorders. It
+	// is empty for edits that are not specific to a single struct.
+	Struct string `json:"struct,omitempty"`
+
+	// Offset is the 0-based byte offset where the edit starts.
+	Offset int `json:"offset"`
+
+	// Length is the number of bytes replaced.
+	Length int `json:"length"`
+
+	// Text is the replacement text.
+	Text string `json:"text"`
+}
+
+// Reporter collects the results of a run and writes them in one format.
+type Reporter interface {
+	// Add records the result of processing a single file.
+	Add(file *File)
+
+	// Flush writes the recorded results to w.
+	Flush(w io.Writer) error
+}
+
+// Formats lists the names of the supported formats, in addition to the
+// plain text output of the command.
+var Formats = []string{"json", "sarif", "checkstyle", "junit", "github", "gitlab"}
+
+// New returns a Reporter for the named format.
+func New(format string) (Reporter, error) {
+	switch format {
+	case "json":
+		return &JSONReporter{}, nil
+	case "sarif":
+		return &SARIFReporter{}, nil
+	case "checkstyle":
+		return &CheckstyleReporter{}, nil
+	case "junit":
+		return &JUnitReporter{}, nil
+	case "github":
+		return &GitHubReporter{}, nil
+	case "gitlab":
+		return &GitLabReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want text or one of: %s)", format, strings.Join(Formats, ", "))
+	}
+}
@@ -0,0 +1,79 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+func TestNew(t *testing.T) {
+	for _, format := range report.Formats {
+		if _, err := report.New(format); err != nil {
+			t.Errorf("New(%q): unexpected error: %v", format, err)
+		}
+	}
+
+	if _, err := report.New("yaml"); err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Errorf("New(yaml): expected unknown format error, got %v", err)
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	r, err := report.New("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &report.File{
+		Path: "a.go",
+		Violations: []report.Violation{{
+			Type:     "exported",
+			Message:  "unexported method stop should appear after exported method Run",
+			Position: report.Position{Filename: "a.go", Offset: 40, Line: 4, Column: 1},
+			Struct:   "S",
+			Method:   "stop",
+			Target:   "Run",
+		}},
+		Fixed: true,
+		Moves: []report.Move{
+			{Struct: "S", Method: "stop", From: 0, To: 1},
+			{Struct: "S", Method: "Run", From: 1, To: 0},
+		},
+	}
+	r.Add(want)
+	r.Add(&report.File{Path: "b.go", Error: "failed to parse file"})
+
+	var buf bytes.Buffer
+	if err := r.Flush(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var files []report.File
+	if err := json.Unmarshal(buf.Bytes(), &files); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	got, _ := json.Marshal(files[0])
+	expected, _ := json.Marshal(want)
+	if !bytes.Equal(got, expected) {
+		t.Errorf("round trip mismatch:\ngot  %s\nwant %s", got, expected)
+	}
+	if files[1].Error != "failed to parse file" {
+		t.Errorf("expected error to be reported, got %q", files[1].Error)
+	}
+}
+
+func TestJSONReporter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&report.JSONReporter{}).Flush(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("expected an empty array, got %q", got)
+	}
+}
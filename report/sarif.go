@@ -0,0 +1,238 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "funcorder-fix"
+	toolURI      = "https://github.com/vajrock/funcorder-fix"
+)
+
+// rule describes one of the funcorder rules.
+type rule struct {
+	id          string
+	name        string
+	description string
+}
+
+// rules lists the funcorder rules, keyed by Violation.Type.
+var rules = []rule{
+	{"constructor", "ConstructorOrder", "Constructors must come before the other methods of their struct."},
+	{"exported", "ExportedOrder", "Exported methods must come before unexported methods of their struct."},
+	{"misplaced", "MethodPlacement", "Methods must be declared in the file that declares their struct."},
+	{"alphabetical", "AlphabeticalOrder", "Methods must be sorted by name within their group."},
+}
+
+// SARIFReporter writes all files as a single SARIF 2.1.0 log with one run.
+// Files with a fix carry it as SARIF fixes on each of their results.
+type SARIFReporter struct {
+	files []*File
+}
+
+// Add records the result of processing a single file.
+func (r *SARIFReporter) Add(file *File) {
+	r.files = append(r.files, file)
+}
+
+// Flush writes the SARIF log to w.
+func (r *SARIFReporter) Flush(w io.Writer) error {
+	driver := sarifDriver{
+		Name:           toolName,
+		InformationURI: toolURI,
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := make(map[string]int, len(rules))
+	for i, rl := range rules {
+		ruleIndex[rl.id] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rl.id,
+			Name:                 rl.name,
+			ShortDescription:     sarifMessage{Text: rl.description},
+			DefaultConfiguration: sarifConfiguration{Level: "warning"},
+			HelpURI:              toolURI,
+		})
+	}
+
+	results := []sarifResult{}
+	for _, file := range r.files {
+		uri := sarifURI(file.Path)
+		for _, v := range file.Violations {
+			index, ok := ruleIndex[v.Type]
+			if !ok {
+				return fmt.Errorf("unknown violation type %q", v.Type)
+			}
+			result := sarifResult{
+				RuleID:    v.Type,
+				RuleIndex: index,
+				Level:     "warning",
+				Message:   sarifMessage{Text: v.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: sarifURI(v.Position.Filename)},
+						Region: &sarifRegion{
+							StartLine:   v.Position.Line,
+							StartColumn: v.Position.Column,
+						},
+					},
+				}},
+			}
+			if fix, ok := sarifFixFor(uri, file, v.Struct); ok {
+				result.Fixes = []sarifFix{fix}
+			}
+			results = append(results, result)
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifFixFor returns the fix for a violation of structName in file: the
+// edits that reorder the struct, or all edits of the file when they are not
+// specific to a single struct.
+func sarifFixFor(uri string, file *File, structName string) (sarifFix, bool) {
+	description := fmt.Sprintf("Reorder methods of %s", structName)
+	var replacements []sarifReplacement
+	for _, edit := range file.Edits {
+		if edit.Struct != "" && edit.Struct != structName {
+			continue
+		}
+		if edit.Struct == "" {
+			description = fmt.Sprintf("Fix method order in %s", file.Path)
+		}
+		offset, length := edit.Offset, edit.Length
+		replacements = append(replacements, sarifReplacement{
+			DeletedRegion:   sarifRegion{ByteOffset: &offset, ByteLength: &length},
+			InsertedContent: &sarifArtifactContent{Text: edit.Text},
+		})
+	}
+	if len(replacements) == 0 {
+		return sarifFix{}, false
+	}
+
+	return sarifFix{
+		Description: sarifMessage{Text: description},
+		ArtifactChanges: []sarifArtifactChange{{
+			ArtifactLocation: sarifArtifactLocation{URI: uri},
+			Replacements:     replacements,
+		}},
+	}, true
+}
+
+// sarifURI converts a file path into a SARIF artifact URI: relative paths
+// become relative URIs, absolute paths file URIs.
+func sarifURI(path string) string {
+	slashed := filepath.ToSlash(path)
+	if filepath.IsAbs(path) {
+		if !strings.HasPrefix(slashed, "/") {
+			slashed = "/" + slashed
+		}
+		return "file://" + slashed
+	}
+	return strings.TrimPrefix(slashed, "./")
+}
+
+// The types below mirror the subset of the SARIF 2.1.0 object model that
+// the reporter writes.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	HelpURI              string             `json:"helpUri"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int  `json:"startLine,omitempty"`
+	StartColumn int  `json:"startColumn,omitempty"`
+	ByteOffset  *int `json:"byteOffset,omitempty"`
+	ByteLength  *int `json:"byteLength,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion           `json:"deletedRegion"`
+	InsertedContent *sarifArtifactContent `json:"insertedContent,omitempty"`
+}
+
+type sarifArtifactContent struct {
+	Text string `json:"text"`
+}
@@ -0,0 +1,191 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/vajrock/funcorder-fix/report"
+)
+
+// sarifLog is the part of a SARIF log the tests inspect.
+type sarifLog struct {
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Name  string `json:"name"`
+				Rules []struct {
+					ID string `json:"id"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			RuleIndex int    `json:"ruleIndex"`
+			Message   struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine   int `json:"startLine"`
+						StartColumn int `json:"startColumn"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			Fixes []struct {
+				ArtifactChanges []struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Replacements []struct {
+						DeletedRegion struct {
+							ByteOffset *int `json:"byteOffset"`
+							ByteLength *int `json:"byteLength"`
+						} `json:"deletedRegion"`
+						InsertedContent struct {
+							Text string `json:"text"`
+						} `json:"insertedContent"`
+					} `json:"replacements"`
+				} `json:"artifactChanges"`
+			} `json:"fixes"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func flushSARIF(t *testing.T, files ...*report.File) sarifLog {
+	t.Helper()
+	r, err := report.New("sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		r.Add(f)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Flush(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("expected a single SARIF 2.1.0 run, got %s", buf.String())
+	}
+	return log
+}
+
+func TestSARIFReporter(t *testing.T) {
+	log := flushSARIF(t, &report.File{
+		Path: "pkg/a.go",
+		Violations: []report.Violation{
+			{
+				Type:     "exported",
+				Message:  "unexported method stop should appear after exported method Run",
+				Position: report.Position{Filename: "pkg/a.go", Offset: 40, Line: 4, Column: 1},
+				Struct:   "S",
+				Method:   "stop",
+			},
+			{
+				Type:     "constructor",
+				Message:  "constructor NewT should appear before method Get",
+				Position: report.Position{Filename: "pkg/a.go", Offset: 90, Line: 9, Column: 1},
+				Struct:   "T",
+				Method:   "NewT",
+			},
+		},
+		Fixed: true,
+		Edits: []report.Edit{
+			{Struct: "S", Offset: 0, Length: 10, Text: "func (s *S) Run() {}"},
+			{Struct: "T", Offset: 60, Length: 5, Text: "x"},
+		},
+	})
+
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "funcorder-fix" {
+		t.Errorf("unexpected driver name %q", run.Tool.Driver.Name)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(run.Results))
+	}
+	for _, res := range run.Results {
+		if rules := run.Tool.Driver.Rules; res.RuleIndex >= len(rules) || rules[res.RuleIndex].ID != res.RuleID {
+			t.Errorf("result %s does not point at its rule", res.RuleID)
+		}
+	}
+
+	first := run.Results[0]
+	loc := first.Locations[0].PhysicalLocation
+	if first.RuleID != "exported" || loc.ArtifactLocation.URI != "pkg/a.go" ||
+		loc.Region.StartLine != 4 || loc.Region.StartColumn != 1 {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+
+	// Each result carries only the edits of its own struct.
+	if len(first.Fixes) != 1 || len(first.Fixes[0].ArtifactChanges) != 1 {
+		t.Fatalf("expected a single fix, got %+v", first.Fixes)
+	}
+	reps := first.Fixes[0].ArtifactChanges[0].Replacements
+	if len(reps) != 1 {
+		t.Fatalf("expected 1 replacement for S, got %d", len(reps))
+	}
+	del := reps[0].DeletedRegion
+	if del.ByteOffset == nil || *del.ByteOffset != 0 || del.ByteLength == nil || *del.ByteLength != 10 ||
+		reps[0].InsertedContent.Text != "func (s *S) Run() {}" {
+		t.Errorf("unexpected replacement: %+v", reps[0])
+	}
+	if reps := run.Results[1].Fixes[0].ArtifactChanges[0].Replacements; len(reps) != 1 || *reps[0].DeletedRegion.ByteOffset != 60 {
+		t.Errorf("unexpected replacements for T: %+v", reps)
+	}
+}
+
+func TestSARIFReporter_FileEdits(t *testing.T) {
+	log := flushSARIF(t, &report.File{
+		Path: "/src/a.go",
+		Violations: []report.Violation{{
+			Type:     "misplaced",
+			Message:  "method Close should be declared in a.go",
+			Position: report.Position{Filename: "/src/b.go", Line: 3, Column: 1},
+			Struct:   "S",
+		}},
+		Fixed: true,
+		Edits: []report.Edit{{Offset: 12, Length: 0, Text: "func (s *S) Close() {}\n"}},
+	})
+
+	res := log.Runs[0].Results[0]
+	if uri := res.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "file:///src/b.go" {
+		t.Errorf("expected an absolute file URI, got %q", uri)
+	}
+	if len(res.Fixes) != 1 {
+		t.Fatalf("expected the file-level edit as a fix, got %+v", res.Fixes)
+	}
+	change := res.Fixes[0].ArtifactChanges[0]
+	if change.ArtifactLocation.URI != "file:///src/a.go" || len(change.Replacements) != 1 {
+		t.Errorf("unexpected artifact change: %+v", change)
+	}
+}
+
+func TestSARIFReporter_NoFix(t *testing.T) {
+	log := flushSARIF(t, &report.File{
+		Path: "a.go",
+		Violations: []report.Violation{{
+			Type:     "exported",
+			Position: report.Position{Filename: "a.go", Line: 4, Column: 1},
+			Struct:   "S",
+		}},
+	})
+	if fixes := log.Runs[0].Results[0].Fixes; len(fixes) != 0 {
+		t.Errorf("expected no fixes without edits, got %+v", fixes)
+	}
+
+	empty := flushSARIF(t)
+	if len(empty.Runs[0].Tool.Driver.Rules) != 4 || empty.Runs[0].Results == nil {
+		t.Errorf("expected all rules and an empty result list, got %+v", empty.Runs[0])
+	}
+}
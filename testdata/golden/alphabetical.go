@@ -0,0 +1,32 @@
+package testdata
+
+// Cache stores values by key.
+type Cache struct {
+	items map[string]string
+}
+
+// NewCache creates an empty cache.
+func NewCache() *Cache { return &Cache{items: map[string]string{}} }
+
+// Delete removes key.
+func (c *Cache) Delete(key string) {
+	delete(c.items, key)
+}
+
+// Get returns the value stored under key.
+func (c *Cache) Get(key string) string {
+	return c.items[key]
+}
+
+// Set stores value under key.
+func (c *Cache) Set(key, value string) {
+	c.items[key] = value
+}
+
+// evict removes the oldest value.
+func (c *Cache) evict() {}
+
+// reset drops all values.
+func (c *Cache) reset() {
+	c.items = map[string]string{}
+}
@@ -0,0 +1,27 @@
+package testpkg
+
+import "fmt"
+
+type Service struct {
+	name string
+}
+
+func (s *Service) Name() string {
+	return s.name
+}
+
+// Start runs the service.
+func (s *Service) Start() error {
+	return s.validate()
+}
+
+func (s *Service) describe() string {
+	return fmt.Sprintf("service %s", s.name)
+}
+
+func (s *Service) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("empty name")
+	}
+	return nil
+}
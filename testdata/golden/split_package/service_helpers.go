@@ -0,0 +1,7 @@
+package testpkg
+
+import "fmt"
+
+func (s *Service) format() string {
+	return fmt.Sprint(s.name)
+}
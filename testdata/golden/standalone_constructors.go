@@ -0,0 +1,44 @@
+package testpkg
+
+import "errors"
+
+type Client struct {
+	retries int
+}
+
+// NewClient creates a client with default settings.
+func NewClient() *Client {
+	return &Client{retries: 3}
+}
+
+// MustClient is like NewClientWithRetries but panics on error.
+func MustClient(retries int) Client {
+	c, err := NewClientWithRetries(retries)
+	if err != nil {
+		panic(err)
+	}
+	return *c
+}
+
+func NewClientWithRetries(retries int) (*Client, error) {
+	if retries < 0 {
+		return nil, errors.New("negative retries")
+	}
+	return &Client{retries: retries}, nil
+}
+
+func (c *Client) Do() error {
+	return nil
+}
+
+type Pool struct {
+	size int
+}
+
+func NewPool(size int) *Pool {
+	return &Pool{size: size}
+}
+
+func (p *Pool) Size() int {
+	return p.size
+}
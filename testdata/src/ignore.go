@@ -0,0 +1,29 @@
+package testdata
+
+// Server runs a listener. Its lifecycle methods are kept in call order.
+type Server struct {
+	addr string
+}
+
+// listen opens the listener.
+func (s *Server) listen() {}
+
+// init prepares the server before it starts.
+//
+//funcorder:ignore // called first, kept at the top
+func (s *Server) init() {}
+
+// Start starts the server.
+func (s *Server) Start() {}
+
+// Stop stops the server.
+func (s *Server) Stop() {}
+
+// Pipeline runs its stages in declaration order.
+//
+//nolint:funcorder // stages are listed in execution order
+type Pipeline struct{}
+
+func (p *Pipeline) parse() {}
+
+func (p *Pipeline) Run() {}
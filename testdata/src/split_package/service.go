@@ -0,0 +1,15 @@
+package testpkg
+
+import "fmt"
+
+type Service struct {
+	name string
+}
+
+func (s *Service) describe() string {
+	return fmt.Sprintf("service %s", s.name)
+}
+
+func (s *Service) Name() string {
+	return s.name
+}
@@ -0,0 +1,19 @@
+package testpkg
+
+import "fmt"
+
+func (s *Service) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("empty name")
+	}
+	return nil
+}
+
+// Start runs the service.
+func (s *Service) Start() error {
+	return s.validate()
+}
+
+func (s *Service) format() string {
+	return fmt.Sprint(s.name)
+}